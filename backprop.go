@@ -11,6 +11,8 @@ import (
 	"math"
 	"math/rand"
 	"time"
+
+	"github.com/tadvi/ann/internal/kernel"
 )
 
 // sigmoid helper function
@@ -19,12 +21,10 @@ func sigmoid(x float64) float64 {
 }
 
 // BNode node for backpropagation training based network
+// Weights of nodes that belong to Backprop share storage with network weight matrices.
 type BNode struct {
 	Thr     float64 // threshold
 	Weights []float64
-
-	activ   float64 // activation value
-	error   float64
 }
 
 // NewBNode creates new backpropagation network node.
@@ -36,6 +36,8 @@ func NewBNode(wCount int) *BNode {
 
 // Backprop main backpropagation network.
 // Public members can be persisted to json or database.
+// Input, Hidden and Output layers are node view of the network, weights are
+// stored in contiguous row-major matrices with one row per node.
 type Backprop struct {
 	Input  []*BNode
 	Hidden []*BNode
@@ -43,9 +45,18 @@ type Backprop struct {
 
 	lhRate float64 // learning rate of the hidden layer
 	loRate float64 // learning rate of the output layer
-	
+
 	netInput   []float64
 	desiredOut []float64
+
+	wih    []float64 // weights between input and hidden layer
+	who    []float64 // weights between hidden and output layer
+	hThr   []float64 // thresholds of the hidden layer
+	oThr   []float64 // thresholds of the output layer
+	hActiv []float64 // activation values of the hidden layer
+	oActiv []float64 // activation values of the output layer
+	hError []float64 // errors of the hidden layer
+	oError []float64 // errors of the output layer
 }
 
 // NewBackprop creates new backpropagation network with input, hidden and output layers.
@@ -83,9 +94,66 @@ func NewBackprop(inCount, hideCount, outCount int) *Backprop {
 		n.Output[i].Thr = rand.Float64()
 	}
 
+	n.pack()
 	return n
 }
 
+// pack moves node weights into weight matrices and copies thresholds from nodes.
+// Node weights are pointed to matrix rows, so after the first call changes made
+// through the node view and by training are seen by both.
+func (n *Backprop) pack() {
+	hideLen := len(n.Hidden)
+	outLen := len(n.Output)
+	n.wih = packLayer(n.wih, n.Input, hideLen)
+	n.who = packLayer(n.who, n.Hidden, outLen)
+
+	if len(n.hThr) != hideLen || len(n.oThr) != outLen {
+		n.hThr = make([]float64, hideLen, hideLen)
+		n.oThr = make([]float64, outLen, outLen)
+		n.hActiv = make([]float64, hideLen, hideLen)
+		n.oActiv = make([]float64, outLen, outLen)
+		n.hError = make([]float64, hideLen, hideLen)
+		n.oError = make([]float64, outLen, outLen)
+	}
+	for h, node := range n.Hidden {
+		n.hThr[h] = node.Thr
+	}
+	for o, node := range n.Output {
+		n.oThr[o] = node.Thr
+	}
+}
+
+// unpack copies thresholds back to nodes.
+func (n *Backprop) unpack() {
+	for h, node := range n.Hidden {
+		node.Thr = n.hThr[h]
+	}
+	for o, node := range n.Output {
+		node.Thr = n.oThr[o]
+	}
+}
+
+// packLayer returns matrix with layer node weights as rows.
+func packLayer(w []float64, layer []*BNode, cols int) []float64 {
+	if len(w) != len(layer)*cols {
+		w = make([]float64, len(layer)*cols, len(layer)*cols)
+	}
+	if cols == 0 {
+		return w
+	}
+	for i, node := range layer {
+		if len(node.Weights) != cols {
+			panic(fmt.Sprintf("expected node weights length %d got %d", cols, len(node.Weights)))
+		}
+		row := w[i*cols : (i+1)*cols : (i+1)*cols]
+		if &node.Weights[0] != &row[0] {
+			copy(row, node.Weights)
+			node.Weights = row
+		}
+	}
+	return w
+}
+
 // TrainingData holds single block of inputs and outputs for the training to run.
 type TrainingData struct {
 	Input  []float64
@@ -97,6 +165,8 @@ func (n *Backprop) Train(iterations int, data []*TrainingData) {
 	inputLen := len(n.Input)
 	outputLen := len(n.Output)
 
+	n.pack()
+	defer n.unpack()
 	for i := 0; i < iterations; i++ {
 		for _, tr := range data {
 			if inputLen != len(tr.Input) {
@@ -107,7 +177,7 @@ func (n *Backprop) Train(iterations int, data []*TrainingData) {
 			}
 			n.netInput = tr.Input
 			n.desiredOut = tr.Output
-			n.TrainOnePattern()
+			n.trainOnePattern()
 		}
 	}
 
@@ -115,6 +185,13 @@ func (n *Backprop) Train(iterations int, data []*TrainingData) {
 
 // TrainOnePattern train single pattern.
 func (n *Backprop) TrainOnePattern() {
+	n.pack()
+	n.trainOnePattern()
+	n.unpack()
+}
+
+// trainOnePattern train single pattern on packed network.
+func (n *Backprop) trainOnePattern() {
 	n.calcActivation()
 	n.calcErrorOutput()
 	n.calcErrorHidden()
@@ -130,114 +207,113 @@ func (n *Backprop) SetLearningRates(lhRate, loRate float64) {
 }
 
 func (n *Backprop) calcActivation() {
-	// a loop to set the activations of the hidden layer
-	for h := 0; h < len(n.Hidden); h++ {
-		for i := 0; i < len(n.Input); i++ {
-			n.Hidden[h].activ += n.netInput[i] * n.Input[i].Weights[h]
-		}
+	// set the activations of the hidden layer and calculate its output
+	vecMat(n.hActiv, n.netInput, n.wih)
+	for h := range n.hActiv {
+		n.hActiv[h] = sigmoid(n.hActiv[h] + n.hThr[h])
 	}
 
-	// calculate the output of the hidden
-	for h := 0; h < len(n.Hidden); h++ {
-		n.Hidden[h].activ += n.Hidden[h].Thr
-		n.Hidden[h].activ = sigmoid(n.Hidden[h].activ)
+	// set the activations of the output layer and calculate its output
+	vecMat(n.oActiv, n.hActiv, n.who)
+	for o := range n.oActiv {
+		n.oActiv[o] = sigmoid(n.oActiv[o] + n.oThr[o])
 	}
-
-	// a loop to set the activations of the output layer
-	for o := 0; o < len(n.Output); o++ {
-		for h := 0; h < len(n.Hidden); h++ {
-			n.Output[o].activ += n.Hidden[h].activ * n.Hidden[h].Weights[o]
-		}
-	}
-
-	// calculate the output of the output layer
-	for o := 0; o < len(n.Output); o++ {
-		n.Output[o].activ += n.Output[o].Thr
-		n.Output[o].activ = sigmoid(n.Output[o].activ)
-	}
-
 }
 
 // calcErrorOutput calculates error of each output neuron.
 func (n *Backprop) calcErrorOutput() {
-	for o := 0; o < len(n.Output); o++ {
-		n.Output[o].error = n.Output[o].activ * (1 - n.Output[o].activ) *
-			(n.desiredOut[o] - n.Output[o].activ)
+	for o, activ := range n.oActiv {
+		n.oError[o] = activ * (1 - activ) * (n.desiredOut[o] - activ)
 	}
 }
 
 // calcErrorHidden calculate error of each hidden neuron.
 func (n *Backprop) calcErrorHidden() {
-	for h := 0; h < len(n.Hidden); h++ {
-		for o := 0; o < len(n.Output); o++ {
-			n.Hidden[h].error += n.Hidden[h].Weights[o] * n.Output[o].error
-		}
-		n.Hidden[h].error *= n.Hidden[h].activ * (1 - n.Hidden[h].activ)
+	matVec(n.hError, n.who, n.oError)
+	for h, activ := range n.hActiv {
+		n.hError[h] *= activ * (1 - activ)
 	}
 }
 
 // calcNewThresholds calculate new thresholds for each neuron.
 func (n *Backprop) calcNewThresholds() {
-	// computing the thresholds for next iteration for hidden layer
-	for h := 0; h < len(n.Hidden); h++ {
-		n.Hidden[h].Thr += n.Hidden[h].error * n.lhRate
-	}
-	// computing the thresholds for next iteration for output layer
-	for o := 0; o < len(n.Output); o++ {
-		n.Output[o].Thr += n.Output[o].error * n.loRate
-	}
-
+	kernel.Axpy(n.lhRate, n.hError, n.hThr)
+	kernel.Axpy(n.loRate, n.oError, n.oThr)
 }
 
 // calcNewWeightsHidden calculate new weights between hidden and output.
 func (n *Backprop) calcNewWeightsHidden() {
-	for h := 0; h < len(n.Hidden); h++ {
-		temp := n.Hidden[h].activ * n.loRate
-		for o := 0; o < len(n.Output); o++ {
-			n.Hidden[h].Weights[o] += temp * n.Output[o].error
-		}
-	}
+	addOuter(n.who, n.loRate, n.hActiv, n.oError)
 }
 
-// calcNewWeightsInput .
+// calcNewWeightsInput calculate new weights between input and hidden.
 func (n *Backprop) calcNewWeightsInput() {
-	for i := 0; i < len(n.netInput); i++ {
-		temp := n.netInput[i] * n.lhRate
-		for h := 0; h < len(n.Hidden); h++ {
-			n.Input[i].Weights[h] += temp * n.Hidden[h].error
-		}
-	}
+	addOuter(n.wih, n.lhRate, n.netInput, n.hError)
 }
 
 // calcTotalErrorPattern.
 func (n *Backprop) calcTotalError() float64 {
 	temp := 0.0
-	for o := 0; o < len(n.Output); o++ {
-		temp += n.Output[o].error
+	for _, e := range n.oError {
+		temp += e
 	}
 	return temp
 }
 
 // Predict calculates network output based on provided input, returns raw float64 activation value.
 func (n *Backprop) Predict(input []float64) []float64 {
+	n.pack()
 	n.netInput = input
 	n.calcActivation()
 	out := make([]float64, len(n.Output), len(n.Output))
-	for i, node := range n.Output {
-		out[i] = node.activ
-	}
+	copy(out, n.oActiv)
 	return out
 }
 
 // PredictInt calculates network output based on provided input, this is main method to call after Train.
 func (n *Backprop) PredictInt(input []float64) []int {
+	n.pack()
 	n.netInput = input
 	n.calcActivation()
 	out := make([]int, len(n.Output), len(n.Output))
-	for i, node := range n.Output {
-		if node.activ > 0.5 {
+	for i, activ := range n.oActiv {
+		if activ > 0.5 {
 			out[i] = 1
 		}
 	}
 	return out
 }
+
+// PredictBatch calculates network outputs for number of inputs at once.
+func (n *Backprop) PredictBatch(inputs [][]float64) [][]float64 {
+	n.pack()
+	inLen := len(n.Input)
+	hideLen := len(n.Hidden)
+	outLen := len(n.Output)
+	m := len(inputs)
+
+	x := make([]float64, m*inLen, m*inLen)
+	for i, input := range inputs {
+		if len(input) != inLen {
+			panic(fmt.Sprintf("expected input length %d got %d", inLen, len(input)))
+		}
+		copy(x[i*inLen:], input)
+	}
+
+	hidden := make([]float64, m*hideLen, m*hideLen)
+	matMul(hidden, x, n.wih, m, inLen, hideLen)
+	for i := range hidden {
+		hidden[i] = sigmoid(hidden[i] + n.hThr[i%hideLen])
+	}
+
+	out := make([]float64, m*outLen, m*outLen)
+	matMul(out, hidden, n.who, m, hideLen, outLen)
+	res := make([][]float64, m, m)
+	for i := range res {
+		res[i] = out[i*outLen : (i+1)*outLen : (i+1)*outLen]
+		for o := range res[i] {
+			res[i][o] = sigmoid(res[i][o] + n.oThr[o])
+		}
+	}
+	return res
+}
@@ -0,0 +1,201 @@
+// Artificial Neural Networks (ann) library in Go
+// Backpropagation Network with float32 precision - Backprop32
+// Same algorithm as Backprop, uses half of the memory.
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// sigmoid32 helper function
+func sigmoid32(x float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(x))))
+}
+
+// Backprop32 backpropagation network with float32 weights.
+// Public members can be persisted to json or database.
+type Backprop32 struct {
+	InputWeights  []float32 // weights between input and hidden layer, row per input node
+	HiddenWeights []float32 // weights between hidden and output layer, row per hidden node
+	HiddenThr     []float32 // thresholds of the hidden layer
+	OutputThr     []float32 // thresholds of the output layer
+
+	lhRate float32 // learning rate of the hidden layer
+	loRate float32 // learning rate of the output layer
+
+	netInput   []float32
+	desiredOut []float32
+
+	hActiv []float32 // activation values of the hidden layer
+	oActiv []float32 // activation values of the output layer
+	hError []float32 // errors of the hidden layer
+	oError []float32 // errors of the output layer
+}
+
+// NewBackprop32 creates new float32 backpropagation network with input, hidden and output layers.
+func NewBackprop32(inCount, hideCount, outCount int) *Backprop32 {
+	n := &Backprop32{
+		lhRate:        0.15,
+		loRate:        0.2,
+		InputWeights:  make([]float32, inCount*hideCount, inCount*hideCount),
+		HiddenWeights: make([]float32, hideCount*outCount, hideCount*outCount),
+		HiddenThr:     make([]float32, hideCount, hideCount),
+		OutputThr:     make([]float32, outCount, outCount),
+	}
+	rand.Seed(time.Now().Unix())
+	for i := range n.InputWeights {
+		n.InputWeights[i] = rand.Float32() - 0.49999
+	}
+	for i := range n.HiddenWeights {
+		n.HiddenWeights[i] = rand.Float32()
+	}
+
+	// reset thresholds
+	for i := range n.HiddenThr {
+		n.HiddenThr[i] = rand.Float32()
+	}
+	for i := range n.OutputThr {
+		n.OutputThr[i] = rand.Float32()
+	}
+	return n
+}
+
+// NewBackprop32From creates float32 copy of the backpropagation network.
+func NewBackprop32From(src *Backprop) *Backprop32 {
+	inCount, hideCount, outCount := len(src.Input), len(src.Hidden), len(src.Output)
+	n := &Backprop32{
+		lhRate:        float32(src.lhRate),
+		loRate:        float32(src.loRate),
+		InputWeights:  make([]float32, 0, inCount*hideCount),
+		HiddenWeights: make([]float32, 0, hideCount*outCount),
+		HiddenThr:     make([]float32, hideCount, hideCount),
+		OutputThr:     make([]float32, outCount, outCount),
+	}
+	for _, node := range src.Input {
+		for _, w := range node.Weights {
+			n.InputWeights = append(n.InputWeights, float32(w))
+		}
+	}
+	for h, node := range src.Hidden {
+		for _, w := range node.Weights {
+			n.HiddenWeights = append(n.HiddenWeights, float32(w))
+		}
+		n.HiddenThr[h] = float32(node.Thr)
+	}
+	for o, node := range src.Output {
+		n.OutputThr[o] = float32(node.Thr)
+	}
+	return n
+}
+
+// TrainingData32 holds single block of float32 inputs and outputs for the training to run.
+type TrainingData32 struct {
+	Input  []float32
+	Output []float32
+}
+
+// init allocates activation and error vectors.
+func (n *Backprop32) init() {
+	hideLen := len(n.HiddenThr)
+	outLen := len(n.OutputThr)
+	if len(n.hActiv) != hideLen || len(n.oActiv) != outLen {
+		n.hActiv = make([]float32, hideLen, hideLen)
+		n.oActiv = make([]float32, outLen, outLen)
+		n.hError = make([]float32, hideLen, hideLen)
+		n.oError = make([]float32, outLen, outLen)
+	}
+}
+
+// inputLen returns size of the input layer.
+func (n *Backprop32) inputLen() int {
+	if len(n.HiddenThr) == 0 {
+		return 0
+	}
+	return len(n.InputWeights) / len(n.HiddenThr)
+}
+
+// Train performs network training for number of iterations, usually over 2000 iterations.
+func (n *Backprop32) Train(iterations int, data []*TrainingData32) {
+	inputLen := n.inputLen()
+	outputLen := len(n.OutputThr)
+
+	n.init()
+	for i := 0; i < iterations; i++ {
+		for _, tr := range data {
+			if inputLen != len(tr.Input) {
+				panic(fmt.Sprintf("expected training data input length %d got %d", inputLen, len(tr.Input)))
+			}
+			if outputLen != len(tr.Output) {
+				panic(fmt.Sprintf("expected traing data output length %d got %d", outputLen, len(tr.Output)))
+			}
+			n.netInput = tr.Input
+			n.desiredOut = tr.Output
+			n.trainOnePattern()
+		}
+	}
+}
+
+// trainOnePattern train single pattern.
+func (n *Backprop32) trainOnePattern() {
+	n.calcActivation()
+
+	// errors of the output and hidden layer
+	for o, activ := range n.oActiv {
+		n.oError[o] = activ * (1 - activ) * (n.desiredOut[o] - activ)
+	}
+	matVec32(n.hError, n.HiddenWeights, n.oError)
+	for h, activ := range n.hActiv {
+		n.hError[h] *= activ * (1 - activ)
+	}
+
+	// new thresholds and weights
+	axpy32(n.lhRate, n.hError, n.HiddenThr)
+	axpy32(n.loRate, n.oError, n.OutputThr)
+	addOuter32(n.HiddenWeights, n.loRate, n.hActiv, n.oError)
+	addOuter32(n.InputWeights, n.lhRate, n.netInput, n.hError)
+}
+
+// SetLearningRates sets learning rate for the backpropagation.
+func (n *Backprop32) SetLearningRates(lhRate, loRate float32) {
+	n.lhRate = lhRate
+	n.loRate = loRate
+}
+
+func (n *Backprop32) calcActivation() {
+	vecMat32(n.hActiv, n.netInput, n.InputWeights)
+	for h := range n.hActiv {
+		n.hActiv[h] = sigmoid32(n.hActiv[h] + n.HiddenThr[h])
+	}
+	vecMat32(n.oActiv, n.hActiv, n.HiddenWeights)
+	for o := range n.oActiv {
+		n.oActiv[o] = sigmoid32(n.oActiv[o] + n.OutputThr[o])
+	}
+}
+
+// Predict calculates network output based on provided input, returns raw float32 activation value.
+func (n *Backprop32) Predict(input []float32) []float32 {
+	n.init()
+	n.netInput = input
+	n.calcActivation()
+	out := make([]float32, len(n.oActiv), len(n.oActiv))
+	copy(out, n.oActiv)
+	return out
+}
+
+// PredictInt calculates network output based on provided input, this is main method to call after Train.
+func (n *Backprop32) PredictInt(input []float32) []int {
+	n.init()
+	n.netInput = input
+	n.calcActivation()
+	out := make([]int, len(n.oActiv), len(n.oActiv))
+	for i, activ := range n.oActiv {
+		if activ > 0.5 {
+			out[i] = 1
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// toTrainingData32 converts training data into float32.
+func toTrainingData32(tr []*TrainingData) []*TrainingData32 {
+	res := []*TrainingData32{}
+	for _, data := range tr {
+		res = append(res, &TrainingData32{Input: toFloat32(data.Input), Output: toFloat32(data.Output)})
+	}
+	return res
+}
+
+// toFloat32 converts slice into float32.
+func toFloat32(values []float64) []float32 {
+	res := make([]float32, len(values), len(values))
+	for i, v := range values {
+		res[i] = float32(v)
+	}
+	return res
+}
+
+// TestBackprop32 trains float32 and float64 networks from the same initial weights
+// on prime numbers and bounds difference of their outputs.
+func TestBackprop32(t *testing.T) {
+	tr := primesTrainingData()
+	tr32 := toTrainingData32(tr)
+	nn := NewBackprop(10, 19, 1)
+	nn32 := NewBackprop32From(nn)
+
+	// untrained networks give the same outputs
+	for _, data := range tr {
+		expected := nn.Predict(data.Input)[0]
+		out := nn32.Predict(toFloat32(data.Input))[0]
+		if math.Abs(float64(out)-expected) > 1e-5 {
+			t.Fatal("expected", expected, "got", out)
+		}
+	}
+
+	// rounding differences grow with training, short training keeps them small
+	nn.Train(100, tr)
+	nn32.Train(100, tr32)
+	for _, data := range tr {
+		expected := nn.Predict(data.Input)[0]
+		out := nn32.Predict(toFloat32(data.Input))[0]
+		if math.Abs(float64(out)-expected) > 1e-4 {
+			t.Fatal("expected", expected, "got", out)
+		}
+	}
+}
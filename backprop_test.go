@@ -7,6 +7,7 @@ package ann
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	//"math/rand"
 )
@@ -107,3 +108,100 @@ func TestBackprop(t *testing.T) {
 		t.Fatal("total errors should be around 14 to 28, got errors", errorCount)
 	}
 }
+
+// primesTrainingData creates training data for the prime numbers up to 1000.
+func primesTrainingData() []*TrainingData {
+	checkPrimes := map[int]bool{}
+	for _, pr := range primes {
+		checkPrimes[pr] = true
+	}
+	tr := []*TrainingData{}
+	for i := 0; i < 1000; i++ {
+		data := &TrainingData{
+			Input:  make([]float64, 10, 10),
+			Output: make([]float64, 1, 1),
+		}
+		for j := 0; j < 10; j++ {
+			data.Input[j] = float64((i >> uint(9-j)) & 1)
+		}
+		if checkPrimes[i] {
+			data.Output[0] = 1
+		}
+		tr = append(tr, data)
+	}
+	return tr
+}
+
+// BenchmarkBackpropTrain measures single training pass over primes data.
+func BenchmarkBackpropTrain(b *testing.B) {
+	tr := primesTrainingData()
+	nn := NewBackprop(10, 19, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nn.Train(1, tr)
+	}
+}
+
+// BenchmarkBackpropTrainWide measures training pass over primes data with wide hidden layer.
+func BenchmarkBackpropTrainWide(b *testing.B) {
+	tr := primesTrainingData()
+	nn := NewBackprop(10, 256, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nn.Train(1, tr)
+	}
+}
+
+// BenchmarkBackpropPredict measures predictions over primes data.
+func BenchmarkBackpropPredict(b *testing.B) {
+	tr := primesTrainingData()
+	nn := NewBackprop(10, 19, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, data := range tr {
+			nn.Predict(data.Input)
+		}
+	}
+}
+
+// BenchmarkBackpropPredictBatch measures batch predictions over primes data.
+func BenchmarkBackpropPredictBatch(b *testing.B) {
+	tr := primesTrainingData()
+	inputs := make([][]float64, len(tr), len(tr))
+	for i, data := range tr {
+		inputs[i] = data.Input
+	}
+	nn := NewBackprop(10, 19, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nn.PredictBatch(inputs)
+	}
+}
+
+// TestBackpropNodeView checks that node view and weight matrices stay in sync.
+func TestBackpropNodeView(t *testing.T) {
+	tr := primesTrainingData()[:50]
+	nn := NewBackprop(10, 5, 1)
+	nn.Train(10, tr)
+
+	// changes made through the node view are used by predictions
+	before := nn.Predict(tr[7].Input)
+	nn.Hidden[0].Weights[0] += 1
+	nn.Output[0].Thr += 1
+	after := nn.Predict(tr[7].Input)
+	if after[0] <= before[0] {
+		t.Fatal("expected node view changes to increase output, got", before, after)
+	}
+
+	// batch predictions match single predictions
+	inputs := [][]float64{}
+	for _, data := range tr {
+		inputs = append(inputs, data.Input)
+	}
+	for i, out := range nn.PredictBatch(inputs) {
+		expected := nn.Predict(inputs[i])
+		if math.Abs(out[0]-expected[0]) > 1e-12 {
+			t.Fatal("expected", expected, "got", out)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Artificial Neural Networks (ann) library in Go
+// Batch training for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/tadvi/ann/internal/kernel"
+)
+
+// TrainBatch performs batch SOM training for specified number of epochs.
+// Every epoch finds best matching nodes for all inputs first and then replaces
+// every node with neighborhood weighted mean of the inputs. Learning rate is not used,
+// result does not depend on the order of inputs and work is spread over all CPU cores.
+// Radius follows radius schedule over epochs, nil neighborhood means Gaussian.
+// Weighted mean needs non-negative weights, so neighborhood is clipped to non-negative
+// values and negative lobe of MexicanHat has no effect in batch mode.
+func (som *SOM) TrainBatch(epochs int, fvInputTrain [][]float64, pvInputTrain [][]float64) {
+	if len(fvInputTrain) != len(pvInputTrain) {
+		panic("length of fvInputTrain should match pvInputTrain")
+	}
+	som.initialize(fvInputTrain, pvInputTrain)
+
+	neighborhood := som.neighborhood
+	if neighborhood == nil {
+		neighborhood = Gaussian{}
+	}
+	length := len(fvInputTrain)
+	best := make([]int, length, length)
+	hits := make([]float64, som.total, som.total)
+	fvSum := make([]float64, som.total*som.fvSize, som.total*som.fvSize)
+	pvSum := make([]float64, som.total*som.pvSize, som.total*som.pvSize)
+	// number of inputs summed per value, missing NaN values are not counted
+	fvCount := make([]float64, som.total*som.fvSize, som.total*som.fvSize)
+	pvCount := make([]float64, som.total*som.pvSize, som.total*som.pvSize)
+
+	for e := 1; e < epochs+1; e++ {
+		radius := som.radius.At(e, epochs)
+
+		// assign inputs to best matching nodes
+		som.buildIndex()
+		parallel(length, func(lo, hi int) {
+			for j := lo; j < hi; j++ {
+				best[j] = som.batchMatch(fvInputTrain[j])
+			}
+		})
+
+		// sum inputs per node, so nodes are combined instead of inputs below
+		for k := range hits {
+			hits[k] = 0
+		}
+		for m := range fvSum {
+			fvSum[m] = 0
+			fvCount[m] = 0
+		}
+		for m := range pvSum {
+			pvSum[m] = 0
+			pvCount[m] = 0
+		}
+		for j, b := range best {
+			hits[b]++
+			addPresent(fvSum[b*som.fvSize:(b+1)*som.fvSize], fvCount[b*som.fvSize:(b+1)*som.fvSize], fvInputTrain[j][:som.fvSize])
+			addPresent(pvSum[b*som.pvSize:(b+1)*som.pvSize], pvCount[b*som.pvSize:(b+1)*som.pvSize], pvInputTrain[j][:som.pvSize])
+		}
+
+		// every node becomes weighted mean of the inputs
+		parallel(som.total, func(lo, hi int) {
+			fv := make([]float64, som.fvSize, som.fvSize)
+			pv := make([]float64, som.pvSize, som.pvSize)
+			fvWeight := make([]float64, som.fvSize, som.fvSize)
+			pvWeight := make([]float64, som.pvSize, som.pvSize)
+			for k := lo; k < hi; k++ {
+				for m := range fv {
+					fv[m] = 0
+					fvWeight[m] = 0
+				}
+				for m := range pv {
+					pv[m] = 0
+					pvWeight[m] = 0
+				}
+				for b, count := range hits {
+					if count == 0 {
+						continue
+					}
+					influence := neighborhood.Influence(som.distance(som.nodes[b], som.nodes[k]), radius)
+					if influence <= 0 {
+						continue
+					}
+					kernel.Axpy(influence, fvSum[b*som.fvSize:(b+1)*som.fvSize], fv)
+					kernel.Axpy(influence, pvSum[b*som.pvSize:(b+1)*som.pvSize], pv)
+					kernel.Axpy(influence, fvCount[b*som.fvSize:(b+1)*som.fvSize], fvWeight)
+					kernel.Axpy(influence, pvCount[b*som.pvSize:(b+1)*som.pvSize], pvWeight)
+				}
+				// values without inputs are kept
+				for m := range fv {
+					if fvWeight[m] > 0 {
+						som.nodes[k].fv[m] = fv[m] / fvWeight[m]
+					}
+				}
+				for m := range pv {
+					if pvWeight[m] > 0 {
+						som.nodes[k].pv[m] = pv[m] / pvWeight[m]
+					}
+				}
+			}
+		})
+		som.index = nil
+		som.trackQuality()
+	}
+	som.buildIndex()
+}
+
+// addPresent adds values that are not NaN to sum and counts them.
+func addPresent(sum, count, values []float64) {
+	for m, v := range values {
+		if !math.IsNaN(v) {
+			sum[m] += v
+			count[m]++
+		}
+	}
+}
+
+// parallel splits range [0, n) into continuous chunks and calls f for each chunk
+// in its own goroutine, one goroutine per CPU core.
+func parallel(n int, f func(lo, hi int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			f(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
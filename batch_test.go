@@ -0,0 +1,110 @@
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// copySOM creates map with the same nodes as som.
+func copySOM(som *SOM) *SOM {
+	res := NewSOM(som.height, som.width, som.fvSize, som.pvSize)
+	for k, node := range som.nodes {
+		copy(res.nodes[k].fv, node.fv)
+		copy(res.nodes[k].pv, node.pv)
+	}
+	return res
+}
+
+// seedSOM sets node vectors from fixed seed, NewSOM seeds every node from the clock.
+func seedSOM(som *SOM, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+	for _, node := range som.nodes {
+		for m := range node.fv {
+			node.fv[m] = r.Float64()
+		}
+		for m := range node.pv {
+			node.pv[m] = r.Float64()
+		}
+	}
+}
+
+// TestSOMTrainBatch trains map with batch algorithm on basic patterns
+// and checks that order of inputs does not change the result.
+func TestSOMTrainBatch(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(12, 12, 10, 3)
+	seedSOM(som, 1)
+	reversed := copySOM(som)
+	before := som.Quality(data, 0).QuantizationError
+	som.TrainBatch(50, data, result)
+	if after := som.Quality(data, 0).QuantizationError; after > before/2 {
+		t.Fatal("expected quantization error to halve got", before, after)
+	}
+
+	// patterns have distinct best matching nodes, more training at the final radius
+	// keeps them matched as closely with the same prediction
+	bmus := map[int]bool{}
+	for i, fv := range data {
+		bmus[som.bestMatch(fv)] = true
+		if res := som.Predict(fv); argmax(res) != i {
+			t.Fatal("expected", i, "got", res)
+		}
+	}
+	if len(bmus) != len(data) {
+		t.Fatal("expected distinct best matching nodes got", bmus)
+	}
+	more := copySOM(som)
+	more.SetRadius(Schedule{Initial: som.radius.Final, Final: som.radius.Final})
+	more.TrainBatch(10, data, result)
+	for _, fv := range data {
+		_, _, d, _ := som.bestMatches(fv)
+		_, _, d2, _ := more.bestMatches(fv)
+		if d2 > d+1e-9 {
+			t.Fatal("expected best match to stay as close for", fv, "got", d2, d)
+		}
+		expected, res := som.Predict(fv), more.Predict(fv)
+		for m := range res {
+			if math.Abs(res[m]-expected[m]) > 1e-6 {
+				t.Fatal("expected stable prediction", expected, "got", res)
+			}
+		}
+	}
+
+	reversed.TrainBatch(50,
+		[][]float64{data[2], data[1], data[0]},
+		[][]float64{result[2], result[1], result[0]})
+	for k, node := range som.nodes {
+		for m := range node.fv {
+			if math.Abs(node.fv[m]-reversed.nodes[k].fv[m]) > 1e-12 {
+				t.Fatal("expected same nodes for reversed inputs, got", node, reversed.nodes[k])
+			}
+		}
+	}
+}
+
+// clipped is neighborhood clipped to non-negative values.
+type clipped struct{ Neighborhood }
+
+// Influence implements Neighborhood.
+func (c clipped) Influence(dist, radius float64) float64 {
+	return math.Max(c.Neighborhood.Influence(dist, radius), 0)
+}
+
+// TestTrainBatchClipped checks that batch training clips negative neighborhood.
+func TestTrainBatchClipped(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(6, 6, 10, 3)
+	other := copySOM(som)
+	som.SetNeighborhood(MexicanHat{})
+	som.TrainBatch(20, data, result)
+	other.SetNeighborhood(clipped{MexicanHat{}})
+	other.TrainBatch(20, data, result)
+	for k, node := range som.nodes {
+		for m := range node.fv {
+			if node.fv[m] != other.nodes[k].fv[m] {
+				t.Fatal("expected clipped neighborhood nodes, got", node, other.nodes[k])
+			}
+		}
+	}
+}
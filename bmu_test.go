@@ -0,0 +1,63 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// gridSOM creates 3 by 4 map where node in column x and row y has feature vector
+// {10·x, 10·y}, values are far outside of the unit square.
+func gridSOM() *SOM {
+	som := NewSOM(3, 4, 2, 1)
+	for _, node := range som.nodes {
+		node.fv[0] = 10 * float64(node.x)
+		node.fv[1] = 10 * float64(node.y)
+	}
+	return som
+}
+
+// TestBMU checks best matching node for inputs outside of the unit hypercube.
+func TestBMU(t *testing.T) {
+	som := gridSOM()
+	x, y, dist := som.BMU([]float64{29, 21})
+	if x != 3 || y != 2 || math.Abs(dist-math.Sqrt2) > 1e-12 {
+		t.Fatal("expected node 3, 2 at distance", math.Sqrt2, "got", x, y, dist)
+	}
+
+	best, second := som.BestMatches([]float64{12, 3})
+	if best.X != 1 || best.Y != 0 || second.X != 1 || second.Y != 1 {
+		t.Fatal("expected nodes 1, 0 and 1, 1 got", best, second)
+	}
+	if math.Abs(best.Dist-math.Sqrt(13)) > 1e-12 || math.Abs(second.Dist-math.Sqrt(53)) > 1e-12 {
+		t.Fatal("unexpected distances", best, second)
+	}
+}
+
+// TestBMUTiesAndNaN checks that ties go to the first node, NaN nodes are skipped
+// and missing values of input are left out.
+func TestBMUTiesAndNaN(t *testing.T) {
+	som := gridSOM()
+	// halfway between 0, 0 and 1, 0
+	best, second := som.BestMatches([]float64{5, 0})
+	if best.X != 0 || best.Y != 0 || second.X != 1 || second.Y != 0 || best.Dist != second.Dist {
+		t.Fatal("expected tie to go to 0, 0 got", best, second)
+	}
+
+	som.nodes[0].fv[0] = math.NaN()
+	if x, y, _ := som.BMU([]float64{0, 0}); x != 1 || y != 0 {
+		t.Fatal("expected NaN node to be skipped got", x, y)
+	}
+
+	// missing value is left out, first of the nodes in row 1 matches
+	if x, y, dist := som.BMU([]float64{math.NaN(), 10}); x != 0 || y != 1 || dist != 0 {
+		t.Fatal("expected node 0, 1 at distance 0 got", x, y, dist)
+	}
+	if x, y, dist := som.BMU([]float64{math.NaN(), math.NaN()}); x != 0 || y != 0 || !math.IsNaN(dist) {
+		t.Fatal("expected first node with NaN distance got", x, y, dist)
+	}
+
+	single := NewSOM(1, 1, 2, 1)
+	if _, second := single.BestMatches([]float64{0, 0}); second.X != -1 || !math.IsInf(second.Dist, 1) {
+		t.Fatal("expected no second node got", second)
+	}
+}
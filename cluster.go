@@ -0,0 +1,317 @@
+// Artificial Neural Networks (ann) library in Go
+// Clustering of Self-Organizing Map nodes
+// released under MIT license
+package ann
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tadvi/ann/internal/kernel"
+)
+
+// Clustering groups nodes of trained map. KMeans, Ward and DaviesBouldin use
+// Euclidean distances between nodes, Cluster and Assign match inputs with the map
+// metric. It keeps copy of the map codebook, so later training or growing the map
+// does not change it.
+type Clustering struct {
+	K       int         // number of clusters
+	Nodes   [][]int     // cluster of every node indexed by row and column
+	Centers [][]float64 // mean feature vector of nodes in every cluster
+
+	codebook [][]float64 // node feature vectors when clustering was created
+	metric   Metric      // map metric used to find best matching node
+	cluster  []int       // cluster of every node, row by row
+}
+
+// newClustering creates clustering from cluster of every node, clusters are
+// numbered from zero in order of their first node.
+func newClustering(som *SOM, cluster []int) *Clustering {
+	c := &Clustering{codebook: som.Codebook(), metric: som.metric, cluster: cluster}
+	ids := map[int]int{}
+	for k, id := range cluster {
+		if _, ok := ids[id]; !ok {
+			ids[id] = len(ids)
+		}
+		cluster[k] = ids[id]
+	}
+	c.K = len(ids)
+
+	c.Nodes = make([][]int, som.height, som.height)
+	for y := range c.Nodes {
+		// rows are copies, so changing them does not change Cluster
+		c.Nodes[y] = append([]int{}, cluster[y*som.width:(y+1)*som.width]...)
+	}
+	c.Centers, _ = som.centers(cluster, c.K)
+	return c
+}
+
+// Cluster returns cluster of the best matching node for fv.
+func (c *Clustering) Cluster(fv []float64) int {
+	best, minimum := 0, math.Inf(1)
+	for k, node := range c.codebook {
+		if temp := metricDistance(c.metric, node, fv[:len(node)]); temp < minimum {
+			best, minimum = k, temp
+		}
+	}
+	return c.cluster[best]
+}
+
+// Assign returns cluster of every input fv.
+func (c *Clustering) Assign(fv [][]float64) []int {
+	res := make([]int, len(fv), len(fv))
+	for i, input := range fv {
+		res[i] = c.Cluster(input)
+	}
+	return res
+}
+
+// DaviesBouldin returns Davies-Bouldin index of node clusters, average similarity of every
+// cluster with its most similar cluster. Lower is better, compare it between clusterings
+// with different number of clusters to pick one. Zero for single cluster.
+func (c *Clustering) DaviesBouldin() float64 {
+	if c.K < 2 {
+		return 0
+	}
+	scatter := make([]float64, c.K, c.K)
+	counts := make([]int, c.K, c.K)
+	for k, node := range c.codebook {
+		id := c.cluster[k]
+		scatter[id] += math.Sqrt(kernel.SqDist(node, c.Centers[id]))
+		counts[id]++
+	}
+	for i := range scatter {
+		scatter[i] /= float64(counts[i])
+	}
+
+	total := 0.0
+	for i := 0; i < c.K; i++ {
+		worst := 0.0
+		for j := 0; j < c.K; j++ {
+			if i != j {
+				d := math.Sqrt(kernel.SqDist(c.Centers[i], c.Centers[j]))
+				if d == 0 {
+					return math.Inf(1) // clusters with the same center
+				}
+				worst = math.Max(worst, (scatter[i]+scatter[j])/d)
+			}
+		}
+		total += worst
+	}
+	return total / float64(c.K)
+}
+
+// KMeans clusters node feature vectors into k clusters with k-means, starting
+// from k-means++ seeding and running at most iterations rounds.
+func (som *SOM) KMeans(k, iterations int) *Clustering {
+	if k < 1 || k > som.total {
+		panic(fmt.Sprintf("number of clusters %d should be in range [1, %d]", k, som.total))
+	}
+	if iterations < 1 {
+		panic(fmt.Sprintf("number of iterations %d should be positive", iterations))
+	}
+
+	// k-means++ seeding, next center is picked with probability proportional
+	// to squared distance from the closest center
+	centers := [][]float64{copyVector(som.nodes[rand.Intn(som.total)].fv)}
+	closest := make([]float64, som.total, som.total)
+	for len(centers) < k {
+		sum := 0.0
+		for n, node := range som.nodes {
+			closest[n] = math.Inf(1)
+			for _, center := range centers {
+				closest[n] = math.Min(closest[n], kernel.SqDist(node.fv, center))
+			}
+			sum += closest[n]
+		}
+		pick := 0
+		for r := rand.Float64() * sum; pick < som.total-1; pick++ {
+			if r -= closest[pick]; r < 0 {
+				break
+			}
+		}
+		centers = append(centers, copyVector(som.nodes[pick].fv))
+	}
+
+	cluster := make([]int, som.total, som.total)
+	for i := 0; i < iterations; i++ {
+		changed := false
+		for n, node := range som.nodes {
+			best, minimum := 0, math.Inf(1)
+			for c, center := range centers {
+				if temp := kernel.SqDist(node.fv, center); temp < minimum {
+					best, minimum = c, temp
+				}
+			}
+			if i == 0 || cluster[n] != best {
+				cluster[n] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		var counts []int
+		centers, counts = som.centers(cluster, k)
+		// empty cluster takes node farthest from its center among clusters
+		// with more than one node, so no other cluster becomes empty
+		for c := 0; c < k; c++ {
+			if counts[c] > 0 {
+				continue
+			}
+			far, maximum := 0, -1.0
+			for n, node := range som.nodes {
+				if counts[cluster[n]] < 2 {
+					continue
+				}
+				if temp := kernel.SqDist(node.fv, centers[cluster[n]]); temp > maximum {
+					far, maximum = n, temp
+				}
+			}
+			cluster[far] = c
+			centers, counts = som.centers(cluster, k)
+		}
+	}
+	return newClustering(som, cluster)
+}
+
+// Ward clusters nodes into k clusters with agglomerative clustering, merging pair
+// of clusters with the smallest increase of within cluster variance. Only clusters
+// that are neighbors on the grid are merged, so every cluster is contiguous area of the map.
+func (som *SOM) Ward(k int) *Clustering {
+	if k < 1 || k > som.total {
+		panic(fmt.Sprintf("number of clusters %d should be in range [1, %d]", k, som.total))
+	}
+
+	// every node starts as its own cluster, merged clusters point to
+	// cluster they were merged into
+	parent := make([]int, som.total, som.total)
+	sizes := make([]float64, som.total, som.total)
+	centers := make([][]float64, som.total, som.total)
+	adjacent := make([]map[int]bool, som.total, som.total)
+	versions := make([]int, som.total, som.total)
+	for n, node := range som.nodes {
+		parent[n] = n
+		sizes[n] = 1
+		centers[n] = copyVector(node.fv)
+		adjacent[n] = map[int]bool{}
+		for _, m := range som.neighbors(n) {
+			adjacent[n][m] = true
+		}
+	}
+
+	queue := &mergeQueue{}
+	push := func(a, b int) {
+		if a > b {
+			a, b = b, a
+		}
+		cost := sizes[a] * sizes[b] / (sizes[a] + sizes[b]) * kernel.SqDist(centers[a], centers[b])
+		heap.Push(queue, merge{cost: cost, a: a, b: b, va: versions[a], vb: versions[b]})
+	}
+	for a := range adjacent {
+		for b := range adjacent[a] {
+			if a < b {
+				push(a, b)
+			}
+		}
+	}
+
+	for count := som.total; count > k && queue.Len() > 0; {
+		m := heap.Pop(queue).(merge)
+		a, b := m.a, m.b
+		if adjacent[a] == nil || adjacent[b] == nil || m.va != versions[a] || m.vb != versions[b] {
+			continue // one of the clusters changed since pair was queued
+		}
+
+		// merge b into a
+		for i := range centers[a] {
+			centers[a][i] = (sizes[a]*centers[a][i] + sizes[b]*centers[b][i]) / (sizes[a] + sizes[b])
+		}
+		sizes[a] += sizes[b]
+		versions[a]++
+		parent[b] = a
+		for j := range adjacent[b] {
+			delete(adjacent[j], b)
+			if j != a {
+				adjacent[j][a] = true
+				adjacent[a][j] = true
+			}
+		}
+		adjacent[b] = nil
+		for j := range adjacent[a] {
+			push(a, j)
+		}
+		count--
+	}
+
+	cluster := make([]int, som.total, som.total)
+	for n := range cluster {
+		cluster[n] = root(parent, n)
+	}
+	return newClustering(som, cluster)
+}
+
+// root returns cluster node n was merged into, compressing path on the way.
+func root(parent []int, n int) int {
+	for parent[n] != n {
+		parent[n] = parent[parent[n]]
+		n = parent[n]
+	}
+	return n
+}
+
+// merge is candidate pair of clusters a < b for Ward, with cluster versions
+// at the time cost was computed.
+type merge struct {
+	cost   float64
+	a, b   int
+	va, vb int
+}
+
+// mergeQueue is heap of merges ordered by cost and then by clusters.
+type mergeQueue []merge
+
+func (q mergeQueue) Len() int { return len(q) }
+
+func (q mergeQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	if q[i].a != q[j].a {
+		return q[i].a < q[j].a
+	}
+	return q[i].b < q[j].b
+}
+
+func (q mergeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *mergeQueue) Push(x interface{}) { *q = append(*q, x.(merge)) }
+
+func (q *mergeQueue) Pop() interface{} {
+	old := *q
+	m := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return m
+}
+
+// centers returns mean feature vector and number of nodes in every cluster.
+func (som *SOM) centers(cluster []int, k int) ([][]float64, []int) {
+	centers := make([][]float64, k, k)
+	counts := make([]int, k, k)
+	for c := range centers {
+		centers[c] = make([]float64, som.fvSize, som.fvSize)
+	}
+	for n, node := range som.nodes {
+		kernel.Axpy(1, node.fv, centers[cluster[n]])
+		counts[cluster[n]]++
+	}
+	for c, center := range centers {
+		for m := range center {
+			center[m] /= math.Max(float64(counts[c]), 1)
+		}
+	}
+	return centers, counts
+}
@@ -0,0 +1,119 @@
+package ann
+
+import "testing"
+
+// splitSOM returns 3 by 4 map with two groups of nodes, columns 0-1 and 2-3.
+func splitSOM() *SOM {
+	som := gridSOM()
+	for _, node := range som.nodes {
+		if node.x >= 2 {
+			node.fv[0] += 100
+		}
+	}
+	return som
+}
+
+// checkSplit checks that clustering separates the two groups of splitSOM.
+func checkSplit(t *testing.T, c *Clustering) {
+	if c.K != 2 {
+		t.Fatal("expected 2 clusters got", c.K)
+	}
+	for y, row := range c.Nodes {
+		for x, id := range row {
+			if id != x/2 {
+				t.Fatal("unexpected clusters", c.Nodes, "at", x, y)
+			}
+		}
+	}
+	if res := c.Assign([][]float64{{0, 0}, {130, 20}}); res[0] != 0 || res[1] != 1 {
+		t.Fatal("unexpected input clusters", res)
+	}
+}
+
+// TestKMeans checks k-means clustering of nodes.
+func TestKMeans(t *testing.T) {
+	som := splitSOM()
+	checkSplit(t, som.KMeans(2, 100))
+	if c := som.KMeans(12, 100); c.K != 12 {
+		t.Fatal("expected cluster per node got", c.Nodes)
+	}
+
+	// clustering keeps nodes it was created from
+	c := som.KMeans(2, 100)
+	som.insertRow(1)
+	som.insertColumn(-1)
+	checkSplit(t, c)
+	c.Nodes[0][2] = 0
+	if id := c.Cluster([]float64{120, 0}); id != 1 {
+		t.Fatal("expected cluster 1 after changing Nodes got", id)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected KMeans to panic without iterations")
+		}
+	}()
+	som.KMeans(2, 0)
+}
+
+// TestWard checks agglomerative clustering and Davies-Bouldin index.
+func TestWard(t *testing.T) {
+	som := splitSOM()
+	two := som.Ward(2)
+	checkSplit(t, two)
+	if c := som.Ward(1); c.K != 1 || c.DaviesBouldin() != 0 {
+		t.Fatal("expected single cluster got", c.Nodes)
+	}
+	if three := som.Ward(3); two.DaviesBouldin() >= three.DaviesBouldin() {
+		t.Fatal("expected 2 clusters to score better than 3 got", two.DaviesBouldin(), three.DaviesBouldin())
+	}
+
+	// groups that are not neighbors on the grid are not merged
+	som = gridSOM()
+	for _, node := range som.nodes {
+		if node.x == 0 || node.x == 3 {
+			node.fv[0] = 0
+		} else {
+			node.fv[0] = 1000
+		}
+	}
+	c := som.Ward(3)
+	if c.Nodes[0][0] == c.Nodes[0][3] || c.Nodes[0][1] != c.Nodes[0][2] {
+		t.Fatal("expected contiguous clusters got", c.Nodes)
+	}
+}
+
+// TestWardLarge checks that every Ward cluster of larger map is contiguous.
+func TestWardLarge(t *testing.T) {
+	som := randomSOM(30, 30, 3, 0.01)
+	c := som.Ward(5)
+	if c.K != 5 {
+		t.Fatal("expected 5 clusters got", c.K)
+	}
+
+	// flood fill from first node of every cluster reaches all of its nodes
+	seen := make([]bool, som.total)
+	for id := 0; id < c.K; id++ {
+		start := 0
+		for c.cluster[start] != id {
+			start++
+		}
+		stack := []int{start}
+		seen[start] = true
+		for len(stack) > 0 {
+			k := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, n := range som.neighbors(k) {
+				if !seen[n] && c.cluster[n] == id {
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+	for k, ok := range seen {
+		if !ok {
+			t.Fatal("cluster", c.cluster[k], "is not contiguous at node", k)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Artificial Neural Networks (ann) library in Go
+// Code generation for trained Backprop networks
+// released under MIT license
+package ann
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+)
+
+// GenerateGo writes standalone Go source for trained network into w.
+// Generated file belongs to package pkg, holds network weights in fixed size
+// arrays prefixed with name and has unrolled, allocation free function
+//
+//	func <name>Predict(in *[inCount]float64, out *[outCount]float64)
+//
+// that returns same values as Predict. Generated code does not depend on "ann".
+func (n *Backprop) GenerateGo(w io.Writer, pkg, name string) error {
+	inLen := len(n.Input)
+	hideLen := len(n.Hidden)
+	outLen := len(n.Output)
+	if inLen == 0 || hideLen == 0 || outLen == 0 {
+		return fmt.Errorf("network layers can not be empty, got %d, %d, %d", inLen, hideLen, outLen)
+	}
+
+	var buff bytes.Buffer
+	buff.WriteString("// Code generated by ann.GenerateGo. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buff, "package %s\n\n", pkg)
+	buff.WriteString("import \"math\"\n\n")
+
+	// weights and thresholds
+	fmt.Fprintf(&buff, "// %sInputWeights holds weights between input and hidden layer.\n", name)
+	fmt.Fprintf(&buff, "var %sInputWeights = [%d][%d]float64{\n", name, inLen, hideLen)
+	for _, node := range n.Input {
+		writeFloats(&buff, node.Weights)
+		buff.WriteString(",\n")
+	}
+	buff.WriteString("}\n\n")
+
+	fmt.Fprintf(&buff, "// %sHiddenWeights holds weights between hidden and output layer.\n", name)
+	fmt.Fprintf(&buff, "var %sHiddenWeights = [%d][%d]float64{\n", name, hideLen, outLen)
+	for _, node := range n.Hidden {
+		writeFloats(&buff, node.Weights)
+		buff.WriteString(",\n")
+	}
+	buff.WriteString("}\n\n")
+
+	fmt.Fprintf(&buff, "// %sHiddenThr holds thresholds of the hidden layer.\n", name)
+	fmt.Fprintf(&buff, "var %sHiddenThr = [%d]float64", name, hideLen)
+	writeFloats(&buff, thresholds(n.Hidden))
+	buff.WriteString("\n\n")
+
+	fmt.Fprintf(&buff, "// %sOutputThr holds thresholds of the output layer.\n", name)
+	fmt.Fprintf(&buff, "var %sOutputThr = [%d]float64", name, outLen)
+	writeFloats(&buff, thresholds(n.Output))
+	buff.WriteString("\n\n")
+
+	// unrolled forward pass
+	fmt.Fprintf(&buff, "// %sPredict calculates network output based on provided input.\n", name)
+	fmt.Fprintf(&buff, "func %sPredict(in *[%d]float64, out *[%d]float64) {\n", name, inLen, outLen)
+	for h := 0; h < hideLen; h++ {
+		fmt.Fprintf(&buff, "h%d := ", h)
+		for i := 0; i < inLen; i++ {
+			fmt.Fprintf(&buff, "in[%d]*%sInputWeights[%d][%d] + ", i, name, i, h)
+		}
+		fmt.Fprintf(&buff, "%sHiddenThr[%d]\n", name, h)
+		fmt.Fprintf(&buff, "h%d = 1 / (1 + math.Exp(-h%d))\n", h, h)
+	}
+	for o := 0; o < outLen; o++ {
+		fmt.Fprintf(&buff, "o%d := ", o)
+		for h := 0; h < hideLen; h++ {
+			fmt.Fprintf(&buff, "h%d*%sHiddenWeights[%d][%d] + ", h, name, h, o)
+		}
+		fmt.Fprintf(&buff, "%sOutputThr[%d]\n", name, o)
+		fmt.Fprintf(&buff, "out[%d] = 1 / (1 + math.Exp(-o%d))\n", o, o)
+	}
+	buff.WriteString("}\n")
+
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// thresholds collects thresholds of the layer.
+func thresholds(layer []*BNode) []float64 {
+	thr := make([]float64, len(layer), len(layer))
+	for i, node := range layer {
+		thr[i] = node.Thr
+	}
+	return thr
+}
+
+// writeFloats writes composite literal with exact float64 values.
+func writeFloats(buff *bytes.Buffer, values []float64) {
+	buff.WriteString("{")
+	for i, v := range values {
+		if i > 0 {
+			buff.WriteString(", ")
+		}
+		buff.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	buff.WriteString("}")
+}
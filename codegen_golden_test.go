@@ -0,0 +1,40 @@
+// Code generated by ann.GenerateGo. DO NOT EDIT.
+
+package ann
+
+import "math"
+
+// goldenInputWeights holds weights between input and hidden layer.
+var goldenInputWeights = [4][3]float64{
+	{0.8414709848078965, 0.9092974268256816, 0.1411200080598672},
+	{-0.9589242746631385, -0.27941549819892586, 0.6569865987187891},
+	{0.4121184852417566, -0.5440211108893699, -0.9999902065507035},
+	{0.4201670368266409, 0.9906073556948702, 0.6502878401571169},
+}
+
+// goldenHiddenWeights holds weights between hidden and output layer.
+var goldenHiddenWeights = [3][2]float64{
+	{-0.9613974918795569, -0.7509872467716762},
+	{0.9129452507276277, 0.836655638536056},
+	{-0.8462204041751706, -0.9055783620066239},
+}
+
+// goldenHiddenThr holds thresholds of the hidden layer.
+var goldenHiddenThr = [3]float64{0.9887046181866692, -0.9999608263946371, 0.9912028118634736}
+
+// goldenOutputThr holds thresholds of the output layer.
+var goldenOutputThr = [2]float64{0.6469193223286404, -0.2921388087338362}
+
+// goldenPredict calculates network output based on provided input.
+func goldenPredict(in *[4]float64, out *[2]float64) {
+	h0 := in[0]*goldenInputWeights[0][0] + in[1]*goldenInputWeights[1][0] + in[2]*goldenInputWeights[2][0] + in[3]*goldenInputWeights[3][0] + goldenHiddenThr[0]
+	h0 = 1 / (1 + math.Exp(-h0))
+	h1 := in[0]*goldenInputWeights[0][1] + in[1]*goldenInputWeights[1][1] + in[2]*goldenInputWeights[2][1] + in[3]*goldenInputWeights[3][1] + goldenHiddenThr[1]
+	h1 = 1 / (1 + math.Exp(-h1))
+	h2 := in[0]*goldenInputWeights[0][2] + in[1]*goldenInputWeights[1][2] + in[2]*goldenInputWeights[2][2] + in[3]*goldenInputWeights[3][2] + goldenHiddenThr[2]
+	h2 = 1 / (1 + math.Exp(-h2))
+	o0 := h0*goldenHiddenWeights[0][0] + h1*goldenHiddenWeights[1][0] + h2*goldenHiddenWeights[2][0] + goldenOutputThr[0]
+	out[0] = 1 / (1 + math.Exp(-o0))
+	o1 := h0*goldenHiddenWeights[0][1] + h1*goldenHiddenWeights[1][1] + h2*goldenHiddenWeights[2][1] + goldenOutputThr[1]
+	out[1] = 1 / (1 + math.Exp(-o1))
+}
@@ -0,0 +1,73 @@
+package ann
+
+import (
+	"bytes"
+	"flag"
+	"math"
+	"os"
+	"testing"
+)
+
+// goldenFile is generated code for goldenBackprop network. It is compiled together
+// with tests, so generated code can be compared with live model.
+// Run "go test -run GenerateGo -update" to regenerate it.
+const goldenFile = "codegen_golden_test.go"
+
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenBackprop creates small network with deterministic weights.
+func goldenBackprop() *Backprop {
+	n := NewBackprop(4, 3, 2)
+	k := 1.0
+	for _, layer := range [][]*BNode{n.Input, n.Hidden, n.Output} {
+		for _, node := range layer {
+			for j := range node.Weights {
+				node.Weights[j] = math.Sin(k)
+				k++
+			}
+			node.Thr = math.Cos(k)
+			k++
+		}
+	}
+	return n
+}
+
+// TestGenerateGo checks generated source against golden file.
+func TestGenerateGo(t *testing.T) {
+	var buff bytes.Buffer
+	if err := goldenBackprop().GenerateGo(&buff, "ann", "golden"); err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.WriteFile(goldenFile, buff.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buff.Bytes(), want) {
+		t.Fatal("generated code does not match", goldenFile, "run go test -update")
+	}
+}
+
+// TestGenerateGoPredict compares output of generated code with live model.
+func TestGenerateGoPredict(t *testing.T) {
+	n := goldenBackprop()
+	var in [4]float64
+	var out [2]float64
+	for i := 0; i < 16; i++ {
+		for j := range in {
+			in[j] = float64((i >> uint(j)) & 1)
+		}
+		in[0] += 0.25 * float64(i)
+		goldenPredict(&in, &out)
+		expected := n.Predict(in[:])
+		for j := range out {
+			if math.Abs(out[j]-expected[j]) > 1e-12 {
+				t.Fatal("expected", expected, "got", out, "for input", in)
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Artificial Neural Networks (ann) library in Go
+// Counterpropagation Network - Kohonen layer followed by Grossberg outstar layer
+// released under MIT license
+package ann
+
+import "fmt"
+
+// CounterProp counterpropagation network of Hecht-Nielsen. Kohonen layer is SOM
+// trained on inputs x, or on joined x and y for full network. Outstar layer learns
+// outputs of every Kohonen node. Forward-only network maps x to y, full network
+// also maps y back to x.
+type CounterProp struct {
+	kohonen     *SOM
+	outstar     [][]float64 // outputs of every Kohonen node, y and then x for full network
+	xSize       int
+	ySize       int
+	full        bool
+	winners     int // number of winning Kohonen nodes, 1 is winner take all
+	outstarRate Schedule
+}
+
+// NewCounterProp creates counterpropagation network with height by width Kohonen layer.
+// Kohonen layer starts from linear initialization.
+func NewCounterProp(height, width, xSize, ySize int, full bool) *CounterProp {
+	fvSize, outSize := xSize, ySize
+	if full {
+		fvSize, outSize = xSize+ySize, ySize+xSize
+	}
+	cp := &CounterProp{
+		kohonen:     NewSOM(height, width, fvSize, 0),
+		outstar:     make([][]float64, height*width, height*width),
+		xSize:       xSize,
+		ySize:       ySize,
+		full:        full,
+		winners:     1,
+		outstarRate: Schedule{Initial: 0.1, Final: 0.01, Decay: Exponential},
+	}
+	cp.kohonen.SetInitialization(LinearInit)
+	for k := range cp.outstar {
+		cp.outstar[k] = make([]float64, outSize, outSize)
+	}
+	return cp
+}
+
+// Kohonen returns Kohonen layer, use it to set topology or schedules before Train.
+func (cp *CounterProp) Kohonen() *SOM {
+	return cp.kohonen
+}
+
+// SetWinners sets number of winning Kohonen nodes, default 1 is winner take all.
+// More winners interpolate outputs weighted by inverse distance to the input.
+func (cp *CounterProp) SetWinners(k int) {
+	if k < 1 {
+		panic(fmt.Sprintf("number of winners should be positive, got %d", k))
+	}
+	cp.winners = k
+}
+
+// SetOutstarRate sets schedule of the outstar learning rate, default decays exponentially
+// from 0.1 to 0.01. Schedule runs over all inputs of all iterations.
+func (cp *CounterProp) SetOutstarRate(outstarRate Schedule) {
+	outstarRate.check()
+	cp.outstarRate = outstarRate
+}
+
+// Train trains Kohonen layer for iterations and then outstar layer for iterations.
+func (cp *CounterProp) Train(iterations int, xInputTrain [][]float64, yInputTrain [][]float64) {
+	if len(xInputTrain) != len(yInputTrain) {
+		panic("length of xInputTrain should match yInputTrain")
+	}
+	fv := make([][]float64, len(xInputTrain), len(xInputTrain))
+	pv := make([][]float64, len(xInputTrain), len(xInputTrain))
+	targets := make([][]float64, len(xInputTrain), len(xInputTrain))
+	for i, x := range xInputTrain {
+		if len(x) != cp.xSize || len(yInputTrain[i]) != cp.ySize {
+			panic(fmt.Sprintf("expected input lengths %d and %d got %d and %d",
+				cp.xSize, cp.ySize, len(x), len(yInputTrain[i])))
+		}
+		fv[i] = x
+		pv[i] = []float64{}
+		targets[i] = yInputTrain[i]
+		if cp.full {
+			fv[i] = append(append([]float64{}, x...), yInputTrain[i]...)
+			targets[i] = append(append([]float64{}, yInputTrain[i]...), x...)
+		}
+	}
+	cp.kohonen.Train(iterations, fv, pv)
+
+	steps := iterations * len(fv)
+	step := 0
+	for i := 0; i < iterations; i++ {
+		for j, input := range fv {
+			step++
+			rate := cp.outstarRate.At(step, steps)
+			ks, weights := cp.winning(input, 0, len(input))
+			for w, k := range ks {
+				moveTowards(cp.outstar[k], rate*weights[w], targets[j])
+			}
+		}
+	}
+}
+
+// Predict maps x to y.
+func (cp *CounterProp) Predict(x []float64) []float64 {
+	return cp.recall(x, 0, cp.xSize, 0, cp.ySize)
+}
+
+// PredictX maps y back to x, only full network can do it.
+func (cp *CounterProp) PredictX(y []float64) []float64 {
+	if !cp.full {
+		panic("forward-only network can not map y to x")
+	}
+	return cp.recall(y, cp.xSize, cp.xSize+cp.ySize, cp.ySize, cp.ySize+cp.xSize)
+}
+
+// recall matches input against Kohonen weights [lo, hi) and blends outstar outputs [outLo, outHi).
+func (cp *CounterProp) recall(input []float64, lo, hi, outLo, outHi int) []float64 {
+	out := make([]float64, outHi-outLo, outHi-outLo)
+	ks, weights := cp.winning(input, lo, hi)
+	for w, k := range ks {
+		for m := range out {
+			out[m] += weights[w] * cp.outstar[k][outLo+m]
+		}
+	}
+	return out
+}
+
+// winning returns winning Kohonen nodes for input compared with weights [lo, hi)
+// and their weights summing to 1.
+func (cp *CounterProp) winning(input []float64, lo, hi int) ([]int, []float64) {
+	ks, dist := cp.kohonen.nearest(input, lo, hi, cp.winners)
+	return ks, inverseDistance(ks, dist)
+}
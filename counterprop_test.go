@@ -0,0 +1,89 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// curveData returns points of curve y = (x², 1 - x) for x in [0, 1].
+func curveData(n int) (x, y [][]float64) {
+	for i := 0; i < n; i++ {
+		v := float64(i) / float64(n-1)
+		x = append(x, []float64{v})
+		y = append(y, []float64{v * v, 1 - v})
+	}
+	return x, y
+}
+
+// curveError returns mean absolute error of network mapping between points of the curve.
+func curveError(cp *CounterProp, x, y [][]float64, backward bool) float64 {
+	total, count := 0.0, 0
+	for i := range x {
+		in, expected := x[i], y[i]
+		if backward {
+			in, expected = y[i], x[i]
+		}
+		out := cp.Predict(in)
+		if backward {
+			out = cp.PredictX(in)
+		}
+		for m := range out {
+			total += math.Abs(out[m] - expected[m])
+			count++
+		}
+	}
+	return total / float64(count)
+}
+
+// TestCounterProp checks forward mapping with one and two winners.
+func TestCounterProp(t *testing.T) {
+	x, y := curveData(41)
+	test, testY := curveData(17)
+
+	cp := NewCounterProp(1, 10, 1, 2, false)
+	cp.Train(50, x, y)
+	wta := curveError(cp, test, testY, false)
+	if wta > 0.05 {
+		t.Fatal("expected winner take all error below 0.05 got", wta)
+	}
+	interpolating := NewCounterProp(1, 10, 1, 2, false)
+	interpolating.SetWinners(2)
+	interpolating.Train(50, x, y)
+	if interpolated := curveError(interpolating, test, testY, false); interpolated >= wta {
+		t.Fatal("expected interpolation to lower error got", interpolated, wta)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected forward-only network to panic on PredictX")
+		}
+	}()
+	cp.PredictX(y[0])
+}
+
+// TestCounterPropFull checks mapping in both directions.
+func TestCounterPropFull(t *testing.T) {
+	x, y := curveData(41)
+	cp := NewCounterProp(1, 12, 1, 2, true)
+	cp.SetWinners(2)
+	cp.Train(50, x, y)
+	if e := curveError(cp, x, y, false); e > 0.05 {
+		t.Fatal("expected forward error below 0.05 got", e)
+	}
+	if e := curveError(cp, x, y, true); e > 0.05 {
+		t.Fatal("expected backward error below 0.05 got", e)
+	}
+
+	// Mahalanobis metric compares x and y with their blocks of covariance,
+	// scaled x and unit y covariance keep the nearest nodes and their weights
+	forward, backward := cp.Predict(x[7]), cp.PredictX(y[7])
+	m, err := NewMahalanobis([][]float64{{4, 0, 0}, {0, 1, 0}, {0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.Kohonen().SetMetric(m)
+	res, resX := cp.Predict(x[7]), cp.PredictX(y[7])
+	if math.Abs(res[0]-forward[0]) > 1e-9 || math.Abs(res[1]-forward[1]) > 1e-9 || math.Abs(resX[0]-backward[0]) > 1e-9 {
+		t.Fatal("expected", forward, backward, "got", res, resX)
+	}
+}
@@ -0,0 +1,161 @@
+// Artificial Neural Networks (ann) library in Go
+// PNG and SVG export of Self-Organizing Map planes
+// released under MIT license
+package ann
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// WritePNG writes plane of the map, such as UMatrix or ComponentPlane, as grayscale PNG
+// image into w. Every node is drawn as cell pixels wide square or hexagon depending on
+// map topology, lowest value is white and highest is black.
+func (som *SOM) WritePNG(w io.Writer, plane [][]float64, cell int) error {
+	if err := som.checkPlane(plane, cell); err != nil {
+		return err
+	}
+	width, height := som.imageSize(cell)
+	lo, hi := planeRange(plane)
+	img := image.NewNRGBA(image.Rect(0, 0, int(math.Ceil(width)), int(math.Ceil(height))))
+	for py := 0; py < img.Rect.Dy(); py++ {
+		for px := 0; px < img.Rect.Dx(); px++ {
+			x, y, ok := som.nodeAt((float64(px)+0.5)/float64(cell), (float64(py)+0.5)/float64(cell))
+			if ok {
+				img.Set(px, py, shade(plane[y][x], lo, hi))
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSVG writes plane of the map as SVG image into w, see WritePNG.
+func (som *SOM) WriteSVG(w io.Writer, plane [][]float64, cell int) error {
+	if err := som.checkPlane(plane, cell); err != nil {
+		return err
+	}
+	width, height := som.imageSize(cell)
+	lo, hi := planeRange(plane)
+	size := float64(cell)
+
+	buff := bufio.NewWriter(w)
+	fmt.Fprintf(buff, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\">\n", width, height)
+	for _, node := range som.nodes {
+		c := shade(plane[node.y][node.x], lo, hi)
+		fill := fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+		if c.A == 0 {
+			fill = "none"
+		}
+		cx, cy := som.cellCenter(node.x, node.y)
+		cx, cy = cx*size, cy*size
+		if som.topology == Hexagonal {
+			// pointy top hexagon touching its six neighbors
+			buff.WriteString("<polygon points=\"")
+			r := size / math.Sqrt(3)
+			for i := 0; i < 6; i++ {
+				angle := math.Pi / 6 * float64(2*i+1)
+				fmt.Fprintf(buff, "%.2f,%.2f ", cx+r*math.Cos(angle), cy+r*math.Sin(angle))
+			}
+			fmt.Fprintf(buff, "\" fill=\"%s\"/>\n", fill)
+		} else {
+			fmt.Fprintf(buff, "<rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" fill=\"%s\"/>\n",
+				cx-size/2, cy-size/2, size, size, fill)
+		}
+	}
+	buff.WriteString("</svg>\n")
+	return buff.Flush()
+}
+
+// checkPlane verifies that plane has one value for every node.
+func (som *SOM) checkPlane(plane [][]float64, cell int) error {
+	if cell <= 0 {
+		return fmt.Errorf("cell size has to be positive, got %d", cell)
+	}
+	if len(plane) != som.height {
+		return fmt.Errorf("expected %d plane rows got %d", som.height, len(plane))
+	}
+	for _, row := range plane {
+		if len(row) != som.width {
+			return fmt.Errorf("expected %d plane columns got %d", som.width, len(row))
+		}
+	}
+	return nil
+}
+
+// imageSize returns size of the image in pixels.
+func (som *SOM) imageSize(cell int) (width, height float64) {
+	width, height = float64(som.width), float64(som.height)
+	if som.topology == Hexagonal {
+		// shifted odd rows and hexagon tips above the first and below the last row
+		if som.height > 1 {
+			width += 0.5
+		}
+		height = float64(som.height-1)*math.Sqrt(3)/2 + 2/math.Sqrt(3)
+	}
+	return width * float64(cell), height * float64(cell)
+}
+
+// cellCenter returns center of node cell in node units, image starts at zero.
+func (som *SOM) cellCenter(x, y int) (float64, float64) {
+	px, py := som.topology.position(x, y)
+	if som.topology == Hexagonal {
+		return px + 0.5, py + 1/math.Sqrt(3)
+	}
+	return px + 0.5, py + 0.5
+}
+
+// nodeAt returns node whose cell covers point px, py in node units.
+func (som *SOM) nodeAt(px, py float64) (x, y int, ok bool) {
+	if som.topology != Hexagonal {
+		x, y = int(px), int(py)
+		return x, y, x < som.width && y < som.height
+	}
+	// nearest hexagon center, hexagons are Voronoi cells of the centers
+	best := math.Inf(1)
+	row := int(math.Round((py - 1/math.Sqrt(3)) / (math.Sqrt(3) / 2)))
+	for cy := row - 1; cy <= row+1; cy++ {
+		for cx := int(math.Floor(px)) - 1; cx <= int(math.Floor(px))+1; cx++ {
+			if cx < 0 || cx >= som.width || cy < 0 || cy >= som.height {
+				continue
+			}
+			hx, hy := som.cellCenter(cx, cy)
+			if d := math.Hypot(hx-px, hy-py); d < best {
+				best, x, y = d, cx, cy
+			}
+		}
+	}
+	// outside of the hexagons at the borders
+	return x, y, best <= 1/math.Sqrt(3)
+}
+
+// planeRange returns lowest and highest value of the plane ignoring NaN.
+func planeRange(plane [][]float64) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, row := range plane {
+		for _, v := range row {
+			if !math.IsNaN(v) {
+				lo = math.Min(lo, v)
+				hi = math.Max(hi, v)
+			}
+		}
+	}
+	return lo, hi
+}
+
+// shade returns gray level for value in range [lo, hi], NaN is transparent.
+func shade(v, lo, hi float64) color.NRGBA {
+	if math.IsNaN(v) {
+		return color.NRGBA{}
+	}
+	t := 0.0
+	if hi > lo {
+		t = (v - lo) / (hi - lo)
+	}
+	g := uint8(math.Round(255 * (1 - t)))
+	return color.NRGBA{R: g, G: g, B: g, A: 255}
+}
@@ -0,0 +1,66 @@
+package ann
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestWritePNG checks image size and shades of rectangular map.
+func TestWritePNG(t *testing.T) {
+	som := gridSOM()
+	var buff bytes.Buffer
+	if err := som.WritePNG(&buff, som.ComponentPlane(0), 10); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 40 || b.Dy() != 30 {
+		t.Fatal("expected 40 by 30 image got", b)
+	}
+	// lowest value is white, highest black
+	if r, _, _, _ := img.At(5, 5).RGBA(); r != 0xffff {
+		t.Error("expected white first column got", r)
+	}
+	if r, _, _, _ := img.At(35, 25).RGBA(); r != 0 {
+		t.Error("expected black last column got", r)
+	}
+
+	if err := som.WritePNG(&buff, som.ComponentPlane(0)[:2], 10); err == nil {
+		t.Error("expected error for plane of wrong size")
+	}
+}
+
+// TestWriteHexagonal checks that hexagonal map is drawn with hexagons.
+func TestWriteHexagonal(t *testing.T) {
+	som := gridSOM()
+	som.SetTopology(Hexagonal)
+	plane := som.UMatrix()
+
+	var buff bytes.Buffer
+	if err := som.WriteSVG(&buff, plane, 20); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buff.String(), "<polygon"); n != 12 {
+		t.Fatal("expected 12 hexagons got", n)
+	}
+
+	buff.Reset()
+	if err := som.WritePNG(&buff, plane, 20); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// corner outside of the first hexagon is transparent, its center is not
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Error("expected transparent corner")
+	}
+	if _, _, _, a := img.At(10, 11).RGBA(); a == 0 {
+		t.Error("expected opaque hexagon center")
+	}
+}
@@ -0,0 +1,214 @@
+// Artificial Neural Networks (ann) library in Go
+// Neural Gas - topology free vector quantizer with rank based adaptation
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// gasNode is single node of neural gas networks.
+type gasNode struct {
+	fv  []float64
+	pv  []float64
+	err float64 // accumulated error, GrowingNeuralGas only
+
+	edges map[*gasNode]int // neighbors and age of the edge to them, GrowingNeuralGas only
+}
+
+// newGasNode creates node with random vectors in range [0, 1).
+func newGasNode(fvSize, pvSize int) *gasNode {
+	node := &gasNode{
+		fv:    make([]float64, fvSize, fvSize),
+		pv:    make([]float64, pvSize, pvSize),
+		edges: map[*gasNode]int{},
+	}
+	for i := range node.fv {
+		node.fv[i] = rand.Float64()
+	}
+	for i := range node.pv {
+		node.pv[i] = rand.Float64()
+	}
+	return node
+}
+
+// adapt moves node vectors towards fv and pv by rate, missing NaN and infinite
+// values are skipped.
+func (node *gasNode) adapt(rate float64, fv, pv []float64) {
+	for m := range node.fv {
+		if finite(fv[m]) {
+			node.fv[m] += rate * (fv[m] - node.fv[m])
+		}
+	}
+	for m := range node.pv {
+		if finite(pv[m]) {
+			node.pv[m] += rate * (pv[m] - node.pv[m])
+		}
+	}
+}
+
+// finite reports if v is neither NaN nor infinity.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// gasMatches finds best and second best matching nodes for fv, ties go to the node
+// with lower index, NaN distances never match and missing nodes are -1.
+// Missing values of fv are left out as in SOM.
+func gasMatches(nodes []*gasNode, metric Metric, fv []float64) (best, second int, bestDist, secondDist float64) {
+	s := newMatchSet()
+	for k, node := range nodes {
+		s.add(k, metricDistance(metric, node.fv, fv[:len(node.fv)]))
+	}
+	return s.best, s.second, s.bestDist, s.secondDist
+}
+
+// gasPredict returns prediction vector of the best matching node.
+func gasPredict(nodes []*gasNode, metric Metric, fv []float64) []float64 {
+	best, _, _, _ := gasMatches(nodes, metric, fv)
+	if best < 0 {
+		best = 0
+	}
+	return nodes[best].pv
+}
+
+// gasCodebook returns copies of node feature vectors.
+func gasCodebook(nodes []*gasNode) [][]float64 {
+	res := make([][]float64, len(nodes), len(nodes))
+	for k, node := range nodes {
+		res[k] = copyVector(node.fv)
+	}
+	return res
+}
+
+// gasPrototypes returns copies of node prediction vectors.
+func gasPrototypes(nodes []*gasNode) [][]float64 {
+	res := make([][]float64, len(nodes), len(nodes))
+	for k, node := range nodes {
+		res[k] = copyVector(node.pv)
+	}
+	return res
+}
+
+// NeuralGas network of Martinetz and Schulten. Every input moves all nodes towards
+// itself, node with rank r among the closest nodes moves by learningRate·exp(-r/lambda).
+type NeuralGas struct {
+	nodes        []*gasNode
+	fvSize       int
+	pvSize       int
+	lambda       Schedule // neighborhood range in ranks
+	learningRate Schedule
+	metric       Metric
+}
+
+// NewNeuralGas creates neural gas with size nodes.
+func NewNeuralGas(size, fvSize, pvSize int) *NeuralGas {
+	ng := &NeuralGas{
+		nodes:        make([]*gasNode, size, size),
+		fvSize:       fvSize,
+		pvSize:       pvSize,
+		lambda:       Schedule{Initial: math.Max(float64(size)/2, 1), Final: 0.01, Decay: Exponential},
+		learningRate: Schedule{Initial: 0.5, Final: 0.005, Decay: Exponential},
+		metric:       Euclidean{},
+	}
+	for k := range ng.nodes {
+		ng.nodes[k] = newGasNode(fvSize, pvSize)
+	}
+	return ng
+}
+
+// SetLambda sets schedule of the neighborhood range, default decays exponentially
+// from half of the nodes to 0.01. Schedule runs over all inputs of all iterations.
+func (ng *NeuralGas) SetLambda(lambda Schedule) {
+	lambda.check()
+	ng.lambda = lambda
+}
+
+// SetLearningRate sets schedule of the learning rate, default decays exponentially
+// from 0.5 to 0.005. Schedule runs over all inputs of all iterations.
+func (ng *NeuralGas) SetLearningRate(learningRate Schedule) {
+	learningRate.check()
+	ng.learningRate = learningRate
+}
+
+// SetMetric sets distance metric used to rank nodes, default is Euclidean.
+func (ng *NeuralGas) SetMetric(metric Metric) {
+	checkMetric(metric, ng.fvSize)
+	ng.metric = metric
+}
+
+// Train performs neural gas training for specified number of iterations over inputs.
+func (ng *NeuralGas) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][]float64) {
+	if len(fvInputTrain) != len(pvInputTrain) {
+		panic("length of fvInputTrain should match pvInputTrain")
+	}
+
+	steps := iterations * len(fvInputTrain)
+	order := make([]int, len(ng.nodes), len(ng.nodes))
+	dist := make([]float64, len(ng.nodes), len(ng.nodes))
+	step := 0
+	for i := 0; i < iterations; i++ {
+		for j, fv := range fvInputTrain {
+			step++
+			lambda := ng.lambda.At(step, steps)
+			rate := ng.learningRate.At(step, steps)
+
+			for k, node := range ng.nodes {
+				order[k] = k
+				dist[k] = metricDistance(ng.metric, node.fv, fv[:ng.fvSize])
+			}
+			// ties keep node order, NaN distances are ranked last
+			sort.SliceStable(order, func(a, b int) bool {
+				return dist[order[a]] < dist[order[b]] || !math.IsNaN(dist[order[a]]) && math.IsNaN(dist[order[b]])
+			})
+			if !finite(dist[order[0]]) {
+				continue // input without values to rank nodes by
+			}
+			for r, k := range order {
+				h := math.Exp(-float64(r) / lambda)
+				if h < 1e-6 {
+					break
+				}
+				ng.nodes[k].adapt(rate*h, fv, pvInputTrain[j])
+			}
+		}
+	}
+}
+
+// Predict returns prediction vector of the best matching node.
+func (ng *NeuralGas) Predict(fv []float64) []float64 {
+	return gasPredict(ng.nodes, ng.metric, fv)
+}
+
+// PredictInt performs prediction and rounds resulting values to percentage.
+func (ng *NeuralGas) PredictInt(fv []float64) []int {
+	res := []int{}
+	for _, val := range ng.Predict(fv) {
+		res = append(res, int(val*100))
+	}
+	return res
+}
+
+// BestMatch returns index of the best matching node for fv and distance to it,
+// index is -1 and distance is infinite when all distances are NaN.
+func (ng *NeuralGas) BestMatch(fv []float64) (k int, dist float64) {
+	best, _, bestDist, _ := gasMatches(ng.nodes, ng.metric, fv)
+	return best, bestDist
+}
+
+// Len returns number of nodes.
+func (ng *NeuralGas) Len() int {
+	return len(ng.nodes)
+}
+
+// Codebook returns copies of node feature vectors.
+func (ng *NeuralGas) Codebook() [][]float64 {
+	return gasCodebook(ng.nodes)
+}
+
+// Prototypes returns copies of node prediction vectors.
+func (ng *NeuralGas) Prototypes() [][]float64 {
+	return gasPrototypes(ng.nodes)
+}
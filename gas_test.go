@@ -0,0 +1,73 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// clusterData returns inputs around four corners of the unit square,
+// prediction is 1 for the right corners.
+func clusterData() (fv, pv [][]float64) {
+	for i := 0; i < 80; i++ {
+		x, y := float64(i%2), float64(i/2%2)
+		dx, dy := 0.02*float64(i%5-2), 0.02*float64(i%3-1)
+		fv = append(fv, []float64{x + dx, y + dy})
+		pv = append(pv, []float64{x})
+	}
+	return fv, pv
+}
+
+// checkCorners checks that every corner has a node close to it and predicts its side.
+func checkCorners(t *testing.T, codebook [][]float64, predict func(fv []float64) []float64) {
+	for _, corner := range [][]float64{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		closest := math.Inf(1)
+		for _, fv := range codebook {
+			closest = math.Min(closest, Euclidean{}.Distance(fv, corner))
+		}
+		if closest > 0.1 {
+			t.Fatal("expected node close to corner", corner, "got", closest)
+		}
+		if res := predict(corner); math.Abs(res[0]-corner[0]) > 0.1 {
+			t.Fatal("unexpected prediction for", corner, res)
+		}
+	}
+}
+
+// TestNeuralGas trains neural gas on four clusters.
+func TestNeuralGas(t *testing.T) {
+	fv, pv := clusterData()
+	ng := NewNeuralGas(8, 2, 1)
+	ng.Train(20, fv, pv)
+	if ng.Len() != 8 {
+		t.Fatal("expected 8 nodes got", ng.Len())
+	}
+	checkCorners(t, ng.Codebook(), ng.Predict)
+
+	if k, _ := ng.BestMatch([]float64{math.NaN(), math.NaN()}); k != -1 {
+		t.Fatal("expected no match for NaN input got", k)
+	}
+}
+
+// TestGasMissing checks that missing and infinite input values are not adapted into nodes.
+func TestGasMissing(t *testing.T) {
+	fv, pv := clusterData()
+	// prediction follows x, so only y goes missing
+	for i := 0; i < len(fv); i += 5 {
+		fv[i][1] = math.NaN()
+	}
+	fv[1][1] = math.Inf(1)
+	pv[2][0] = math.NaN()
+
+	ng := NewNeuralGas(8, 2, 1)
+	ng.Train(20, fv, pv)
+	g := NewGrowingNeuralGas(12, 2, 1)
+	g.Train(50, fv, pv)
+	for _, codebook := range [][][]float64{ng.Codebook(), ng.Prototypes(), g.Codebook(), g.Prototypes()} {
+		for _, v := range codebook {
+			if !finite(v[0]) || len(v) > 1 && !finite(v[1]) {
+				t.Fatal("expected finite node vectors got", v)
+			}
+		}
+	}
+	checkCorners(t, ng.Codebook(), ng.Predict)
+}
@@ -0,0 +1,223 @@
+// Artificial Neural Networks (ann) library in Go
+// Growing Neural Gas - neural gas with edges between nodes and node insertion
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"sort"
+)
+
+// GrowingNeuralGas network of Fritzke. Starts with two nodes, connects best and second
+// best matching nodes with edges that age and expire, and inserts new node next to
+// the node with the largest accumulated error every lambda inputs.
+type GrowingNeuralGas struct {
+	nodes    []*gasNode
+	fvSize   int
+	pvSize   int
+	step     int     // inputs seen
+	epsBest  float64 // learning rate of the best matching node
+	epsNeigh float64 // learning rate of its neighbors
+	maxAge   int     // edges older than maxAge are removed
+	lambda   int     // node is inserted every lambda inputs
+	alpha    float64 // error reduction of nodes next to inserted node
+	decay    float64 // error decay after every input
+	maxNodes int
+	metric   Metric
+}
+
+// NewGrowingNeuralGas creates growing neural gas with at most maxNodes nodes.
+func NewGrowingNeuralGas(maxNodes, fvSize, pvSize int) *GrowingNeuralGas {
+	return &GrowingNeuralGas{
+		nodes:    []*gasNode{newGasNode(fvSize, pvSize), newGasNode(fvSize, pvSize)},
+		fvSize:   fvSize,
+		pvSize:   pvSize,
+		epsBest:  0.2,
+		epsNeigh: 0.006,
+		maxAge:   50,
+		lambda:   100,
+		alpha:    0.5,
+		decay:    0.995,
+		maxNodes: maxNodes,
+		metric:   Euclidean{},
+	}
+}
+
+// SetLearningRates sets learning rates of the best matching node and its neighbors,
+// defaults are 0.2 and 0.006.
+func (g *GrowingNeuralGas) SetLearningRates(epsBest, epsNeigh float64) {
+	g.epsBest = epsBest
+	g.epsNeigh = epsNeigh
+}
+
+// SetGrowth sets number of inputs between node insertions and maximal age of edges,
+// defaults are 100 and 50.
+func (g *GrowingNeuralGas) SetGrowth(lambda, maxAge int) {
+	if lambda < 1 || maxAge < 1 {
+		panic(fmt.Sprintf("lambda %d and maxAge %d should be positive", lambda, maxAge))
+	}
+	g.lambda = lambda
+	g.maxAge = maxAge
+}
+
+// SetMetric sets distance metric used to find best matching nodes, default is Euclidean.
+func (g *GrowingNeuralGas) SetMetric(metric Metric) {
+	checkMetric(metric, g.fvSize)
+	g.metric = metric
+}
+
+// Train performs training for specified number of iterations over inputs.
+func (g *GrowingNeuralGas) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][]float64) {
+	if len(fvInputTrain) != len(pvInputTrain) {
+		panic("length of fvInputTrain should match pvInputTrain")
+	}
+	for i := 0; i < iterations; i++ {
+		for j := range fvInputTrain {
+			g.TrainOnePattern(fvInputTrain[j], pvInputTrain[j])
+		}
+	}
+}
+
+// TrainOnePattern adapts network to single input, use it for streaming data.
+func (g *GrowingNeuralGas) TrainOnePattern(fv, pv []float64) {
+	g.step++
+	best, second, bestDist, _ := gasMatches(g.nodes, g.metric, fv)
+	if best < 0 || second < 0 {
+		return // input with NaN distances
+	}
+	s1, s2 := g.nodes[best], g.nodes[second]
+
+	s1.err += bestDist * bestDist
+	s1.adapt(g.epsBest, fv, pv)
+	for n := range s1.edges {
+		n.adapt(g.epsNeigh, fv, pv)
+		s1.edges[n]++
+		n.edges[s1]++
+	}
+	s1.edges[s2] = 0
+	s2.edges[s1] = 0
+
+	// remove old edges and nodes left without edges
+	for n, age := range s1.edges {
+		if age > g.maxAge {
+			delete(s1.edges, n)
+			delete(n.edges, s1)
+		}
+	}
+	nodes := g.nodes[:0]
+	for _, node := range g.nodes {
+		if len(node.edges) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	g.nodes = nodes
+
+	if g.step%g.lambda == 0 && len(g.nodes) < g.maxNodes {
+		g.insert()
+	}
+	for _, node := range g.nodes {
+		node.err *= g.decay
+	}
+}
+
+// insert adds node halfway between node with the largest error and its neighbor
+// with the largest error.
+func (g *GrowingNeuralGas) insert() {
+	var q, f *gasNode
+	for _, node := range g.nodes {
+		if q == nil || node.err > q.err {
+			q = node
+		}
+	}
+	for n := range q.edges {
+		if f == nil || n.err > f.err || n.err == f.err && g.index(n) < g.index(f) {
+			f = n
+		}
+	}
+	if f == nil {
+		return
+	}
+
+	r := &gasNode{
+		fv:    make([]float64, g.fvSize, g.fvSize),
+		pv:    make([]float64, g.pvSize, g.pvSize),
+		edges: map[*gasNode]int{q: 0, f: 0},
+	}
+	for m := range r.fv {
+		r.fv[m] = (q.fv[m] + f.fv[m]) / 2
+	}
+	for m := range r.pv {
+		r.pv[m] = (q.pv[m] + f.pv[m]) / 2
+	}
+	delete(q.edges, f)
+	delete(f.edges, q)
+	q.edges[r] = 0
+	f.edges[r] = 0
+
+	q.err *= g.alpha
+	f.err *= g.alpha
+	r.err = q.err
+	g.nodes = append(g.nodes, r)
+}
+
+// index returns position of the node.
+func (g *GrowingNeuralGas) index(node *gasNode) int {
+	for k, n := range g.nodes {
+		if n == node {
+			return k
+		}
+	}
+	return -1
+}
+
+// Predict returns prediction vector of the best matching node.
+func (g *GrowingNeuralGas) Predict(fv []float64) []float64 {
+	return gasPredict(g.nodes, g.metric, fv)
+}
+
+// PredictInt performs prediction and rounds resulting values to percentage.
+func (g *GrowingNeuralGas) PredictInt(fv []float64) []int {
+	res := []int{}
+	for _, val := range g.Predict(fv) {
+		res = append(res, int(val*100))
+	}
+	return res
+}
+
+// BestMatch returns index of the best matching node for fv and distance to it,
+// index is -1 and distance is infinite when all distances are NaN.
+func (g *GrowingNeuralGas) BestMatch(fv []float64) (k int, dist float64) {
+	best, _, bestDist, _ := gasMatches(g.nodes, g.metric, fv)
+	return best, bestDist
+}
+
+// Len returns number of nodes.
+func (g *GrowingNeuralGas) Len() int {
+	return len(g.nodes)
+}
+
+// Codebook returns copies of node feature vectors.
+func (g *GrowingNeuralGas) Codebook() [][]float64 {
+	return gasCodebook(g.nodes)
+}
+
+// Prototypes returns copies of node prediction vectors.
+func (g *GrowingNeuralGas) Prototypes() [][]float64 {
+	return gasPrototypes(g.nodes)
+}
+
+// Edges returns sorted pairs of connected node indexes, lower index first.
+func (g *GrowingNeuralGas) Edges() [][2]int {
+	res := [][2]int{}
+	for a, node := range g.nodes {
+		for n := range node.edges {
+			if b := g.index(n); a < b {
+				res = append(res, [2]int{a, b})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i][0] < res[j][0] || res[i][0] == res[j][0] && res[i][1] < res[j][1]
+	})
+	return res
+}
@@ -0,0 +1,53 @@
+package ann
+
+import "testing"
+
+// TestGrowingNeuralGas grows network over four clusters.
+func TestGrowingNeuralGas(t *testing.T) {
+	fv, pv := clusterData()
+	g := NewGrowingNeuralGas(12, 2, 1)
+	g.Train(50, fv, pv)
+	if g.Len() < 4 || g.Len() > 12 {
+		t.Fatal("expected between 4 and 12 nodes got", g.Len())
+	}
+	checkCorners(t, g.Codebook(), g.Predict)
+
+	for _, e := range g.Edges() {
+		if e[0] >= e[1] || e[1] >= g.Len() {
+			t.Fatal("unexpected edge", e)
+		}
+	}
+	if len(g.Edges()) == 0 {
+		t.Fatal("expected edges between nodes")
+	}
+}
+
+// TestGrowingNeuralGasStream checks that streaming inputs grow the network
+// and old edges expire.
+func TestGrowingNeuralGasStream(t *testing.T) {
+	fv, pv := clusterData()
+	g := NewGrowingNeuralGas(20, 2, 1)
+	g.SetGrowth(10, 5)
+	for i := 0; i < 40; i++ {
+		for j := range fv {
+			g.TrainOnePattern(fv[j], pv[j])
+		}
+	}
+	if g.Len() <= 2 {
+		t.Fatal("expected network to grow got", g.Len())
+	}
+	// with short edge life corners are not connected to each other
+	codebook := g.Codebook()
+	for _, e := range g.Edges() {
+		if d := (Euclidean{}).Distance(codebook[e[0]], codebook[e[1]]); d > 0.9 {
+			t.Error("unexpected edge between clusters", codebook[e[0]], codebook[e[1]])
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected SetGrowth to panic on zero lambda")
+		}
+	}()
+	g.SetGrowth(0, 5)
+}
@@ -0,0 +1,102 @@
+// Artificial Neural Networks (ann) library in Go
+// Numerical gradient checking for Backprop
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"math"
+)
+
+// GradReport holds largest error between analytical and numerical gradients
+// for each layer of network parameters, see GradCheck.
+type GradReport struct {
+	InputWeights  float64 // weights between input and hidden layer
+	HiddenWeights float64 // weights between hidden and output layer
+	HiddenThr     float64 // thresholds of the hidden layer
+	OutputThr     float64 // thresholds of the output layer
+}
+
+// Max returns largest error over all layers.
+func (r *GradReport) Max() float64 {
+	return math.Max(math.Max(r.InputWeights, r.HiddenWeights), math.Max(r.HiddenThr, r.OutputThr))
+}
+
+// String pretty print report.
+func (r *GradReport) String() string {
+	return fmt.Sprintf("input weights %.3g, hidden weights %.3g, hidden thr %.3g, output thr %.3g",
+		r.InputWeights, r.HiddenWeights, r.HiddenThr, r.OutputThr)
+}
+
+// GradCheck compares gradients used by backpropagation training with central finite
+// differences of squared error loss over data, eps is finite difference step, 1e-5 works well.
+// Error is relative for gradients above 1 and absolute below, where finite difference
+// noise dominates. Network is left unchanged.
+func GradCheck(n *Backprop, data []*TrainingData, eps float64) *GradReport {
+	n.pack()
+	inputLen := len(n.Input)
+	outputLen := len(n.Output)
+
+	// analytical gradients are negated updates made by trainOnePattern without learning rates
+	gih := make([]float64, len(n.wih), len(n.wih))
+	gho := make([]float64, len(n.who), len(n.who))
+	ghThr := make([]float64, len(n.hThr), len(n.hThr))
+	goThr := make([]float64, len(n.oThr), len(n.oThr))
+	for _, tr := range data {
+		if inputLen != len(tr.Input) || outputLen != len(tr.Output) {
+			panic(fmt.Sprintf("expected data lengths %d and %d got %d and %d",
+				inputLen, outputLen, len(tr.Input), len(tr.Output)))
+		}
+		n.netInput = tr.Input
+		n.desiredOut = tr.Output
+		n.calcActivation()
+		n.calcErrorOutput()
+		n.calcErrorHidden()
+		addOuter(gih, -1, n.netInput, n.hError)
+		addOuter(gho, -1, n.hActiv, n.oError)
+		for h, e := range n.hError {
+			ghThr[h] -= e
+		}
+		for o, e := range n.oError {
+			goThr[o] -= e
+		}
+	}
+
+	return &GradReport{
+		InputWeights:  n.gradError(n.wih, gih, data, eps),
+		HiddenWeights: n.gradError(n.who, gho, data, eps),
+		HiddenThr:     n.gradError(n.hThr, ghThr, data, eps),
+		OutputThr:     n.gradError(n.oThr, goThr, data, eps),
+	}
+}
+
+// gradError returns largest error between analytical gradients and central
+// differences for each of the params, see GradCheck.
+func (n *Backprop) gradError(params, grad []float64, data []*TrainingData, eps float64) float64 {
+	worst := 0.0
+	for i, p := range params {
+		params[i] = p + eps
+		plus := n.loss(data)
+		params[i] = p - eps
+		minus := n.loss(data)
+		params[i] = p
+
+		numeric := (plus - minus) / (2 * eps)
+		e := math.Abs(grad[i]-numeric) / math.Max(math.Abs(grad[i])+math.Abs(numeric), 1)
+		worst = math.Max(worst, e)
+	}
+	return worst
+}
+
+// loss calculates squared error loss over data.
+func (n *Backprop) loss(data []*TrainingData) float64 {
+	total := 0.0
+	for _, tr := range data {
+		n.netInput = tr.Input
+		n.calcActivation()
+		for o, activ := range n.oActiv {
+			total += 0.5 * (tr.Output[o] - activ) * (tr.Output[o] - activ)
+		}
+	}
+	return total
+}
@@ -0,0 +1,33 @@
+package ann
+
+import (
+	"testing"
+)
+
+// TestGradCheck verifies backward pass against finite differences
+// before and after some training.
+func TestGradCheck(t *testing.T) {
+	tr := primesTrainingData()[90:110]
+	nn := NewBackprop(10, 7, 1)
+	if report := GradCheck(nn, tr, 1e-5); report.Max() > 1e-6 {
+		t.Fatal("expected gradients to match, got", report)
+	}
+
+	nn.Train(20, tr)
+	if report := GradCheck(nn, tr, 1e-5); report.Max() > 1e-6 {
+		t.Fatal("expected gradients to match after training, got", report)
+	}
+	// wrong gradient of output threshold is reported
+	if e := nn.gradError(nn.oThr, []float64{100}, tr, 1e-5); e < 0.5 {
+		t.Fatal("expected wrong gradient to be reported got", e)
+	}
+
+	// several outputs
+	multi := []*TrainingData{
+		{Input: []float64{0.1, 0.9, 0.4}, Output: []float64{1, 0}},
+		{Input: []float64{0.8, 0.2, 0.3}, Output: []float64{0, 1}},
+	}
+	if report := GradCheck(NewBackprop(3, 4, 2), multi, 1e-5); report.Max() > 1e-6 {
+		t.Fatal("expected gradients to match for several outputs, got", report)
+	}
+}
@@ -0,0 +1,178 @@
+// Artificial Neural Networks (ann) library in Go
+// Growing Self-Organizing Map - map grows rows and columns where it fits inputs poorly
+// released under MIT license
+package ann
+
+import "math"
+
+// GrowingSOM self-organizing map that starts with 2 by 2 nodes and inserts
+// rows and columns of nodes while training. All SOM methods work on the grown map.
+type GrowingSOM struct {
+	*SOM
+	threshold float64 // growth threshold of accumulated quantization error
+	maxNodes  int
+}
+
+// NewGrowingSOM creates growing map, row or column is inserted next to node
+// whose accumulated quantization error is above threshold.
+func NewGrowingSOM(fvSize, pvSize int, threshold float64) *GrowingSOM {
+	return &GrowingSOM{
+		SOM:       NewSOM(2, 2, fvSize, pvSize),
+		threshold: threshold,
+		maxNodes:  1000,
+	}
+}
+
+// SetMaxNodes limits size of the map, default is 1000 nodes.
+func (g *GrowingSOM) SetMaxNodes(maxNodes int) {
+	g.maxNodes = maxNodes
+}
+
+// Train trains the map for iterations and then inserts row or column between node with
+// the largest accumulated quantization error and its most distant neighbor, training
+// repeats until errs of all nodes are within threshold or map would grow over the limit.
+// Initial radius is scaled with height plus width of the map after every insertion,
+// final radius and learning rate stay as set. Hexagonal maps insert rows in pairs.
+// Returns number of inserted rows and columns.
+func (g *GrowingSOM) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][]float64) int {
+	inserted := 0
+	for {
+		g.SOM.Train(iterations, fvInputTrain, pvInputTrain)
+
+		errs := make([]float64, g.total, g.total)
+		for _, fv := range fvInputTrain {
+			best, _, bestDist, _ := g.bestMatches(fv)
+			if !math.IsNaN(bestDist) {
+				errs[best] += bestDist
+			}
+		}
+		worst := 0
+		for k, e := range errs {
+			if e > errs[worst] {
+				worst = k
+			}
+		}
+		if errs[worst] <= g.threshold {
+			return inserted
+		}
+
+		// most distant neighbor of the worst node
+		node := g.nodes[worst]
+		far, maximum := -1, -1.0
+		for _, n := range g.neighbors(worst) {
+			if temp := g.fvDistance(node.fv, g.nodes[n].fv); temp > maximum {
+				far, maximum = n, temp
+			}
+		}
+		if far < 0 {
+			return inserted
+		}
+
+		span := g.height + g.width
+		if other := g.nodes[far]; other.y != node.y {
+			rows := g.insertedRows()
+			if (g.height+rows)*g.width > g.maxNodes {
+				return inserted
+			}
+			g.insertRow(insertAt(node.y, other.y))
+			inserted += rows
+		} else {
+			if g.height*(g.width+1) > g.maxNodes {
+				return inserted
+			}
+			g.insertColumn(insertAt(node.x, other.x))
+			inserted++
+		}
+		g.radius.Initial *= float64(g.height+g.width) / float64(span)
+	}
+}
+
+// insertAt returns position of new row or column between neighbors a and b,
+// neighbors across the edge of toroidal map get it at the end.
+func insertAt(a, b int) int {
+	if a-b == 1 || b-a == 1 {
+		if a > b {
+			return a
+		}
+		return b
+	}
+	return -1
+}
+
+// insertedRows returns number of rows insertRow inserts, hexagonal maps get
+// pair of rows so that rows after it keep their offset.
+func (som *SOM) insertedRows() int {
+	if som.topology == Hexagonal {
+		return 2
+	}
+	return 1
+}
+
+// insertRow inserts row before row r, -1 appends it. New nodes are interpolated
+// between their neighbors in the rows around, see insertedRows.
+func (som *SOM) insertRow(r int) {
+	if r < 0 {
+		r = som.height
+	}
+	rows := som.insertedRows()
+	som.resize(som.height+rows, som.width, func(x, y int) (*SNode, *SNode, float64) {
+		switch {
+		case y < r:
+			return som.nodes[y*som.width+x], nil, 0
+		case y >= r+rows:
+			return som.nodes[(y-rows)*som.width+x], nil, 0
+		}
+		t := float64(y-r+1) / float64(rows+1)
+		return som.nodes[(r-1)*som.width+x], som.nodes[(r%som.height)*som.width+x], t
+	})
+}
+
+// insertColumn inserts column before column c, -1 appends it, see insertRow.
+func (som *SOM) insertColumn(c int) {
+	if c < 0 {
+		c = som.width
+	}
+	som.resize(som.height, som.width+1, func(x, y int) (*SNode, *SNode, float64) {
+		switch {
+		case x < c:
+			return som.nodes[y*som.width+x], nil, 0
+		case x > c:
+			return som.nodes[y*som.width+x-1], nil, 0
+		}
+		return som.nodes[y*som.width+c-1], som.nodes[y*som.width+c%som.width], 0.5
+	})
+}
+
+// resize replaces nodes with new grid, source returns old node for position x, y
+// or two old nodes a and b with weight t, new node is a + t·(b - a).
+func (som *SOM) resize(height, width int, source func(x, y int) (*SNode, *SNode, float64)) {
+	nodes := make([]*SNode, height*width, height*width)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			a, b, t := source(x, y)
+			node := &SNode{
+				fvSize: som.fvSize,
+				pvSize: som.pvSize,
+				y:      y,
+				x:      x,
+				fv:     copyVector(a.fv),
+				pv:     copyVector(a.pv),
+			}
+			if b != nil {
+				for m := range node.fv {
+					node.fv[m] = a.fv[m] + t*(b.fv[m]-a.fv[m])
+				}
+				for m := range node.pv {
+					node.pv[m] = a.pv[m] + t*(b.pv[m]-a.pv[m])
+				}
+			}
+			nodes[y*width+x] = node
+		}
+	}
+	som.nodes = nodes
+	som.height = height
+	som.width = width
+	som.total = height * width
+	som.buildIndex()
+	som.labels = nil
+}
@@ -0,0 +1,102 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestInsertRow checks nodes of inserted row and column.
+func TestInsertRow(t *testing.T) {
+	som := gridSOM()
+	som.insertRow(1)
+	som.insertColumn(-1)
+	if h, w := som.Size(); h != 4 || w != 5 || len(som.nodes) != 20 {
+		t.Fatal("expected 4 by 5 map got", h, w)
+	}
+	for k, node := range som.nodes {
+		if node.x != k%5 || node.y != k/5 {
+			t.Fatal("unexpected node position", k, node.x, node.y)
+		}
+	}
+	// inserted row is mean of rows 0 and 1
+	if fv := som.Node(2, 1).FV(); fv[0] != 20 || fv[1] != 5 {
+		t.Fatal("unexpected inserted row", fv)
+	}
+	if fv := som.Node(2, 2).FV(); fv[1] != 10 {
+		t.Fatal("expected shifted row got", fv)
+	}
+	// appended column is mean of the last and the first column
+	if fv := som.Node(4, 3).FV(); fv[0] != 15 || fv[1] != 20 {
+		t.Fatal("unexpected appended column", fv)
+	}
+
+	// hexagonal map gets pair of rows, old odd rows stay odd
+	som = gridSOM()
+	som.SetTopology(Hexagonal)
+	som.insertRow(1)
+	if h, _ := som.Size(); h != 5 {
+		t.Fatal("expected 5 rows got", h)
+	}
+	for y, expected := range []float64{0, 10.0 / 3, 20.0 / 3, 10, 20} {
+		if fv := som.Node(0, y).FV(); math.Abs(fv[1]-expected) > 1e-9 {
+			t.Fatal("unexpected row", y, fv)
+		}
+	}
+}
+
+// circleData returns inputs on a circle, which 2 by 2 map fits poorly, and their half.
+func circleData() ([][]float64, [][]float64) {
+	fv := [][]float64{}
+	pv := [][]float64{}
+	for i := 0; i < 60; i++ {
+		angle := 2 * math.Pi * float64(i) / 60
+		fv = append(fv, []float64{math.Cos(angle), math.Sin(angle)})
+		pv = append(pv, []float64{float64(i / 30)})
+	}
+	return fv, pv
+}
+
+// TestGrowingSOM grows map over inputs on a circle.
+func TestGrowingSOM(t *testing.T) {
+	fv, pv := circleData()
+
+	small := NewSOM(2, 2, 2, 1)
+	small.SetInitialization(LinearInit)
+	small.Train(20, fv, pv)
+
+	som := NewGrowingSOM(2, 1, 1)
+	som.SetMaxNodes(30)
+	som.SetInitialization(LinearInit)
+	inserted := som.Train(20, fv, pv)
+	h, w := som.Size()
+	if inserted == 0 || h+w != 4+inserted || h*w > 30 {
+		t.Fatal("expected map to grow got", h, w, inserted)
+	}
+	before := small.Quality(fv, 0).QuantizationError
+	if after := som.Quality(fv, 0).QuantizationError; after > before/2 {
+		t.Fatal("expected grown map to halve quantization error got", before, after)
+	}
+	if res := som.Predict([]float64{0, -1}); math.Round(res[0]) != 1 {
+		t.Fatal("unexpected prediction", res)
+	}
+}
+
+// TestGrowingSchedules checks that growing keeps schedules set by user.
+func TestGrowingSchedules(t *testing.T) {
+	fv, pv := circleData()
+	som := NewGrowingSOM(2, 1, 1)
+	som.SetMaxNodes(30)
+	som.SetRadius(Schedule{Initial: 3, Final: 0.5, Decay: Linear})
+	rate := Schedule{Initial: 0.2, Final: 0.01, Decay: Linear}
+	som.SetLearningRate(rate)
+	som.Train(20, fv, pv)
+
+	h, w := som.Size()
+	radius := Schedule{Initial: 3 * float64(h+w) / 4, Final: 0.5, Decay: Linear}
+	if math.Abs(som.radius.Initial-radius.Initial) > 1e-9 || som.radius.Final != radius.Final || som.radius.Decay != radius.Decay {
+		t.Fatal("expected radius", radius, "got", som.radius)
+	}
+	if som.learningRate != rate {
+		t.Fatal("expected learning rate", rate, "got", som.learningRate)
+	}
+}
@@ -0,0 +1,145 @@
+// Artificial Neural Networks (ann) library in Go
+// Initialization of Self-Organizing Map nodes from training data
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Initialization is method used to set node vectors before training.
+type Initialization int
+
+const (
+	// RandomInit keeps random values in range [0, 1) set by NewSOM.
+	RandomInit Initialization = iota
+	// LinearInit spreads feature vectors over the plane of the first two principal
+	// components of the inputs, longer side of the map follows the first component.
+	// Nodes take prediction vector of the closest input. Inputs with missing
+	// NaN values are left out of principal component analysis.
+	LinearInit
+	// SampleInit copies feature and prediction vectors of randomly picked inputs.
+	// Missing NaN values of inputs keep random values.
+	SampleInit
+)
+
+// String returns initialization name.
+func (i Initialization) String() string {
+	switch i {
+	case RandomInit:
+		return "random"
+	case LinearInit:
+		return "linear"
+	case SampleInit:
+		return "sample"
+	}
+	return "unknown"
+}
+
+// SetInitialization sets initialization applied by the next Train or TrainBatch call.
+func (som *SOM) SetInitialization(init Initialization) {
+	som.init = init
+	som.initPending = init != RandomInit
+}
+
+// Initialize sets node vectors from inputs fv and pv using initialization init.
+func (som *SOM) Initialize(init Initialization, fv, pv [][]float64) {
+	if len(fv) != len(pv) {
+		panic("length of fv should match pv")
+	}
+	som.initPending = false
+	som.trained = true
+	if len(fv) == 0 {
+		return
+	}
+	switch init {
+	case LinearInit:
+		som.linearInit(fv, pv)
+	case SampleInit:
+		for _, node := range som.nodes {
+			i := rand.Intn(len(fv))
+			copyPresent(node.fv, fv[i])
+			copyPresent(node.pv, pv[i])
+		}
+	}
+	som.buildIndex()
+}
+
+// initialize applies pending initialization at the start of training.
+func (som *SOM) initialize(fv, pv [][]float64) {
+	som.trained = true
+	if som.initPending {
+		som.Initialize(som.init, fv, pv)
+	}
+}
+
+// linearInit places nodes on the plane of the first two principal components,
+// grid coordinates in range [-1, 1] are scaled by standard deviation of the component.
+func (som *SOM) linearInit(fv, pv [][]float64) {
+	// principal components of inputs without missing values
+	complete := [][]float64{}
+	for _, input := range fv {
+		if !hasNaN(input[:som.fvSize]) {
+			complete = append(complete, input[:som.fvSize])
+		}
+	}
+	if len(complete) == 0 {
+		return
+	}
+	mean, components, variances := pca(complete, 2)
+
+	// grid positions centered at zero
+	px := make([]float64, som.total, som.total)
+	py := make([]float64, som.total, som.total)
+	loX, hiX, loY, hiY := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for k, node := range som.nodes {
+		px[k], py[k] = som.topology.position(node.x, node.y)
+		loX, hiX = math.Min(loX, px[k]), math.Max(hiX, px[k])
+		loY, hiY = math.Min(loY, py[k]), math.Max(hiY, py[k])
+	}
+	// first component along the longer side
+	if hiY-loY > hiX-loX {
+		px, py = py, px
+		loX, hiX, loY, hiY = loY, hiY, loX, hiX
+	}
+
+	for k, node := range som.nodes {
+		a := scaleCoord(px[k], loX, hiX) * math.Sqrt(math.Max(variances[0], 0))
+		b := 0.0
+		if len(components) > 1 {
+			b = scaleCoord(py[k], loY, hiY) * math.Sqrt(math.Max(variances[1], 0))
+		}
+		for m := range node.fv {
+			node.fv[m] = mean[m] + a*components[0][m]
+			if len(components) > 1 {
+				node.fv[m] += b * components[1][m]
+			}
+		}
+
+		closest, minimum := 0, math.Inf(1)
+		for i, input := range fv {
+			if temp := som.fvDistance(node.fv, input); temp < minimum {
+				closest, minimum = i, temp
+			}
+		}
+		copyPresent(node.pv, pv[closest])
+	}
+}
+
+// copyPresent copies values of src that are not NaN into dst.
+func copyPresent(dst, src []float64) {
+	for m := range dst {
+		if !math.IsNaN(src[m]) {
+			dst[m] = src[m]
+		}
+	}
+}
+
+// scaleCoord maps v from range [lo, hi] into [-1, 1], zero for empty range.
+func scaleCoord(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0
+	}
+	return 2*(v-lo)/(hi-lo) - 1
+}
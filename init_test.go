@@ -0,0 +1,74 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestLinearInit checks that nodes span the plane of the inputs.
+func TestLinearInit(t *testing.T) {
+	// inputs on the plane z = 5, wider along x
+	fv := [][]float64{}
+	pv := [][]float64{}
+	for i := 0; i <= 10; i++ {
+		for j := 0; j <= 4; j++ {
+			fv = append(fv, []float64{float64(i), float64(j), 5})
+			pv = append(pv, []float64{float64(i)})
+		}
+	}
+
+	som := NewSOM(3, 6, 3, 1)
+	som.Initialize(LinearInit, fv, pv)
+	first, last := som.nodes[0].fv, som.nodes[5].fv
+	for _, node := range som.nodes {
+		if math.Abs(node.fv[2]-5) > 1e-9 {
+			t.Fatal("expected node on the plane got", node)
+		}
+	}
+	// first row follows the x axis
+	if math.Abs(math.Abs(last[0]-first[0])-2*math.Sqrt(10)) > 1e-9 || math.Abs(last[1]-first[1]) > 1e-9 {
+		t.Fatal("expected first row along x axis got", first, last)
+	}
+	// prediction vector of the closest input
+	if som.nodes[0].pv[0] != math.Round(first[0]) {
+		t.Fatal("unexpected prediction vector", som.nodes[0])
+	}
+}
+
+// TestSampleInit checks that nodes are copies of inputs.
+func TestSampleInit(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(4, 4, 10, 3)
+	som.Initialize(SampleInit, data, result)
+	for _, node := range som.nodes {
+		found := false
+		for i := range data {
+			if som.fvDistance(node.fv, data[i]) == 0 && node.pv[i] == 1 {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("expected copy of an input got", node)
+		}
+	}
+}
+
+// TestInitTrain checks that training starts from selected initialization.
+func TestInitTrain(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(12, 12, 10, 3)
+	som.SetInitialization(LinearInit)
+	before := som.Quality(data, 0).QuantizationError
+	som.TrainBatch(50, data, result)
+	if som.initPending {
+		t.Fatal("expected initialization to be applied once")
+	}
+	if after := som.Quality(data, 0).QuantizationError; after >= before {
+		t.Fatal("expected lower quantization error after training got", before, after)
+	}
+	for i, fv := range data {
+		if res := som.PredictInt(fv); res[i] < 85 {
+			t.Fatal("expected", i, "to be above 85% got", res)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Artificial Neural Networks (ann) library in Go
+// Vector kernels shared by networks
+// released under MIT license
+
+// Package kernel provides dot product, axpy and squared Euclidean distance kernels.
+// Assembly implementations (AVX2 and FMA on amd64, NEON on arm64) are selected at
+// runtime based on CPU features, pure Go versions are used everywhere else
+// or when built with "purego" tag.
+package kernel
+
+// implementations selected by init
+var (
+	dot    = dotGeneric
+	axpy   = axpyGeneric
+	sqDist = sqDistGeneric
+)
+
+// Dot calculates dot product of x and first len(x) elements of y.
+func Dot(x, y []float64) float64 {
+	if len(y) < len(x) {
+		panic("kernel: y is shorter than x")
+	}
+	return dot(x, y)
+}
+
+// Axpy calculates y += a * x for first len(x) elements of y.
+func Axpy(a float64, x, y []float64) {
+	if len(y) < len(x) {
+		panic("kernel: y is shorter than x")
+	}
+	axpy(a, x, y)
+}
+
+// SqDist calculates squared Euclidean distance between x and first len(x) elements of y.
+func SqDist(x, y []float64) float64 {
+	if len(y) < len(x) {
+		panic("kernel: y is shorter than x")
+	}
+	return sqDist(x, y)
+}
@@ -0,0 +1,50 @@
+//go:build !purego
+
+package kernel
+
+//go:noescape
+func dotAVX2(x, y []float64) float64
+
+//go:noescape
+func axpyAVX2(a float64, x, y []float64)
+
+//go:noescape
+func sqDistAVX2(x, y []float64) float64
+
+// cpuid executes CPUID instruction with given EAX and ECX values.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv reads extended control register 0.
+func xgetbv() (eax, edx uint32)
+
+func init() {
+	if hasAVX2FMA() {
+		dot = dotAVX2
+		axpy = axpyAVX2
+		sqDist = sqDistAVX2
+	}
+}
+
+// hasAVX2FMA reports if CPU supports AVX2 and FMA and OS saves YMM registers.
+func hasAVX2FMA() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	_, _, ecx1, _ := cpuid(1, 0)
+	const (
+		fma     = 1 << 12
+		osxsave = 1 << 27
+		avx     = 1 << 28
+	)
+	if ecx1&(fma|osxsave|avx) != fma|osxsave|avx {
+		return false
+	}
+	// XMM and YMM state has to be enabled by OS
+	if eax, _ := xgetbv(); eax&6 != 6 {
+		return false
+	}
+	_, ebx7, _, _ := cpuid(7, 0)
+	const avx2 = 1 << 5
+	return ebx7&avx2 != 0
+}
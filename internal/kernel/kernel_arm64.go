@@ -0,0 +1,19 @@
+//go:build !purego
+
+package kernel
+
+//go:noescape
+func dotNEON(x, y []float64) float64
+
+//go:noescape
+func axpyNEON(a float64, x, y []float64)
+
+//go:noescape
+func sqDistNEON(x, y []float64) float64
+
+// NEON is mandatory on arm64.
+func init() {
+	dot = dotNEON
+	axpy = axpyNEON
+	sqDist = sqDistNEON
+}
@@ -0,0 +1,118 @@
+//go:build !purego
+
+#include "textflag.h"
+
+// func dotNEON(x, y []float64) float64
+TEXT ·dotNEON(SB), NOSPLIT, $0-56
+	MOVD x_base+0(FP), R0
+	MOVD x_len+8(FP), R2
+	MOVD y_base+24(FP), R1
+	VEOR V0.B16, V0.B16, V0.B16
+	VEOR V1.B16, V1.B16, V1.B16
+	LSR  $2, R2, R3
+	CBZ  R3, dotreduce
+
+dotloop:
+	VLD1.P 32(R0), [V2.D2, V3.D2]
+	VLD1.P 32(R1), [V4.D2, V5.D2]
+	VFMLA  V2.D2, V4.D2, V0.D2
+	VFMLA  V3.D2, V5.D2, V1.D2
+	SUB    $1, R3
+	CBNZ   R3, dotloop
+
+dotreduce:
+	VFADD V1.D2, V0.D2, V0.D2
+	VMOV  V0.D[1], R4
+	FMOVD R4, F1
+	FADDD F1, F0, F0
+	AND   $3, R2
+	CBZ   R2, dotdone
+
+dottail:
+	FMOVD.P 8(R0), F2
+	FMOVD.P 8(R1), F3
+	FMULD   F2, F3, F3
+	FADDD   F3, F0, F0
+	SUB     $1, R2
+	CBNZ    R2, dottail
+
+dotdone:
+	FMOVD F0, ret+48(FP)
+	RET
+
+// func axpyNEON(a float64, x, y []float64)
+TEXT ·axpyNEON(SB), NOSPLIT, $0-56
+	MOVD  a+0(FP), R4
+	FMOVD a+0(FP), F0
+	MOVD  x_base+8(FP), R0
+	MOVD  x_len+16(FP), R2
+	MOVD  y_base+32(FP), R1
+	VDUP  R4, V1.D2
+	LSR   $2, R2, R3
+	CBZ   R3, axpytail0
+
+axpyloop:
+	VLD1.P 32(R0), [V2.D2, V3.D2]
+	VLD1   (R1), [V4.D2, V5.D2]
+	VFMLA  V1.D2, V2.D2, V4.D2
+	VFMLA  V1.D2, V3.D2, V5.D2
+	VST1.P [V4.D2, V5.D2], 32(R1)
+	SUB    $1, R3
+	CBNZ   R3, axpyloop
+
+axpytail0:
+	AND $3, R2
+	CBZ R2, axpydone
+
+axpytail:
+	FMOVD.P 8(R0), F2
+	FMOVD   (R1), F3
+	FMULD   F0, F2, F2
+	FADDD   F2, F3, F3
+	FMOVD.P F3, 8(R1)
+	SUB     $1, R2
+	CBNZ    R2, axpytail
+
+axpydone:
+	RET
+
+// func sqDistNEON(x, y []float64) float64
+TEXT ·sqDistNEON(SB), NOSPLIT, $0-56
+	MOVD x_base+0(FP), R0
+	MOVD x_len+8(FP), R2
+	MOVD y_base+24(FP), R1
+	VEOR V0.B16, V0.B16, V0.B16
+	VEOR V1.B16, V1.B16, V1.B16
+	LSR  $2, R2, R3
+	CBZ  R3, sqreduce
+
+sqloop:
+	VLD1.P 32(R0), [V2.D2, V3.D2]
+	VLD1.P 32(R1), [V4.D2, V5.D2]
+	VFSUB  V4.D2, V2.D2, V2.D2
+	VFSUB  V5.D2, V3.D2, V3.D2
+	VFMLA  V2.D2, V2.D2, V0.D2
+	VFMLA  V3.D2, V3.D2, V1.D2
+	SUB    $1, R3
+	CBNZ   R3, sqloop
+
+sqreduce:
+	VFADD V1.D2, V0.D2, V0.D2
+	VMOV  V0.D[1], R4
+	FMOVD R4, F1
+	FADDD F1, F0, F0
+	AND   $3, R2
+	CBZ   R2, sqdone
+
+sqtail:
+	FMOVD.P 8(R0), F2
+	FMOVD.P 8(R1), F3
+	FSUBD   F3, F2, F2
+	FMULD   F2, F2, F2
+	FADDD   F2, F0, F0
+	SUB     $1, R2
+	CBNZ    R2, sqtail
+
+sqdone:
+	FMOVD F0, ret+48(FP)
+	RET
@@ -0,0 +1,89 @@
+package kernel
+
+import (
+	"math"
+	"testing"
+)
+
+// vectors decodes fuzz data into two vectors of small values.
+func vectors(data []byte) ([]float64, []float64) {
+	n := len(data) / 2
+	x := make([]float64, n, n)
+	y := make([]float64, n, n)
+	for i := 0; i < n; i++ {
+		x[i] = float64(int8(data[i])) / 16
+		y[i] = float64(int8(data[n+i])) / 16
+	}
+	return x, y
+}
+
+// near reports if got is within rounding error of expected, scale is sum of magnitudes.
+func near(got, expected, scale float64) bool {
+	return math.Abs(got-expected) <= 1e-12*(scale+1)
+}
+
+// seeds adds inputs covering vector and tail loops.
+func seeds(f *testing.F) {
+	for _, n := range []int{0, 1, 3, 4, 7, 8, 9, 15, 16, 17, 33, 100} {
+		data := make([]byte, 2*n, 2*n)
+		for i := range data {
+			data[i] = byte(i*37 + 11)
+		}
+		f.Add(data, 0.75)
+	}
+}
+
+func FuzzDot(f *testing.F) {
+	seeds(f)
+	f.Fuzz(func(t *testing.T, data []byte, a float64) {
+		x, y := vectors(data)
+		scale := 0.0
+		for i := range x {
+			scale += math.Abs(x[i] * y[i])
+		}
+		got, expected := Dot(x, y), dotGeneric(x, y)
+		if !near(got, expected, scale) {
+			t.Fatal("expected", expected, "got", got, "for", x, y)
+		}
+	})
+}
+
+func FuzzAxpy(f *testing.F) {
+	seeds(f)
+	f.Fuzz(func(t *testing.T, data []byte, a float64) {
+		if math.IsNaN(a) || math.IsInf(a, 0) || math.Abs(a) > 1e6 {
+			return
+		}
+		x, y := vectors(data)
+		expected := append([]float64{}, y...)
+		axpyGeneric(a, x, expected)
+		Axpy(a, x, y)
+		for i := range y {
+			if !near(y[i], expected[i], math.Abs(a*x[i])+math.Abs(expected[i])) {
+				t.Fatal("expected", expected, "got", y, "for", a, x)
+			}
+		}
+	})
+}
+
+func FuzzSqDist(f *testing.F) {
+	seeds(f)
+	f.Fuzz(func(t *testing.T, data []byte, a float64) {
+		x, y := vectors(data)
+		expected := sqDistGeneric(x, y)
+		got := SqDist(x, y)
+		if !near(got, expected, expected) {
+			t.Fatal("expected", expected, "got", got, "for", x, y)
+		}
+	})
+}
+
+// TestLengthMismatch checks that shorter y is rejected before assembly can read past it.
+func TestLengthMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	Dot(make([]float64, 8), make([]float64, 7))
+}
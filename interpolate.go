@@ -0,0 +1,132 @@
+// Artificial Neural Networks (ann) library in Go
+// Interpolated prediction from several best matching nodes of Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Interpolation is weighting of best matching nodes blended by PredictInterpolated.
+type Interpolation int
+
+const (
+	// InverseDistance weights nodes by inverse of their distance to input,
+	// nodes at zero distance take all weight.
+	InverseDistance Interpolation = iota
+	// GaussianKernel weights nodes by exp(-d²/(2σ²)), where σ is distance
+	// of the farthest of the blended nodes.
+	GaussianKernel
+)
+
+// String returns interpolation name.
+func (i Interpolation) String() string {
+	switch i {
+	case InverseDistance:
+		return "inverse distance"
+	case GaussianKernel:
+		return "gaussian kernel"
+	}
+	return "unknown"
+}
+
+// PredictInterpolated blends prediction vectors of k best matching nodes. Confidence
+// in range (0, 1] is 1 / (1 + s), where s is weighted standard deviation of the blended
+// prediction vectors, it is 1 when all nodes predict the same.
+func (som *SOM) PredictInterpolated(fv []float64, k int, mode Interpolation) (pv []float64, confidence float64) {
+	if k < 1 {
+		panic(fmt.Sprintf("number of nodes should be positive, got %d", k))
+	}
+	ks, dist := som.nearest(fv, 0, som.fvSize, k)
+	pv = make([]float64, som.pvSize, som.pvSize)
+	if len(ks) == 0 {
+		return pv, 0
+	}
+
+	var weights []float64
+	switch mode {
+	case GaussianKernel:
+		weights = gaussianWeights(ks, dist)
+	default:
+		weights = inverseDistance(ks, dist)
+	}
+	for w, n := range ks {
+		for m, v := range som.nodes[n].pv {
+			pv[m] += weights[w] * v
+		}
+	}
+
+	spread := 0.0
+	for w, n := range ks {
+		spread += weights[w] * SquaredEuclidean{}.Distance(som.nodes[n].pv, pv)
+	}
+	return pv, 1 / (1 + math.Sqrt(spread))
+}
+
+// nearest returns up to k nodes closest to fv compared with feature values [lo, hi),
+// closest first, and distances of all nodes. Missing values of fv are left out
+// and nodes with NaN distance are skipped. Mahalanobis metric uses covariance
+// of features [lo, hi).
+func (som *SOM) nearest(fv []float64, lo, hi, k int) ([]int, []float64) {
+	metric := rangeMetric(som.metric, lo, hi)
+	dist := make([]float64, som.total, som.total)
+	order := []int{}
+	for n, node := range som.nodes {
+		dist[n] = metricDistance(metric, node.fv[lo:hi], fv[:hi-lo])
+		if !math.IsNaN(dist[n]) {
+			order = append(order, n)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return dist[order[a]] < dist[order[b]] })
+	if len(order) > k {
+		order = order[:k]
+	}
+	return order, dist
+}
+
+// inverseDistance returns weights of nodes ks proportional to inverse of their distances,
+// nodes at zero distance share all weight.
+func inverseDistance(ks []int, dist []float64) []float64 {
+	weights := make([]float64, len(ks), len(ks))
+	exact := 0
+	for _, k := range ks {
+		if dist[k] == 0 {
+			exact++
+		}
+	}
+	sum := 0.0
+	for w, k := range ks {
+		switch {
+		case exact > 0 && dist[k] == 0:
+			weights[w] = 1
+		case exact == 0:
+			weights[w] = 1 / dist[k]
+		}
+		sum += weights[w]
+	}
+	for w := range weights {
+		weights[w] /= sum
+	}
+	return weights
+}
+
+// gaussianWeights returns weights of nodes ks, closest first, from gaussian kernel
+// with width of the largest distance.
+func gaussianWeights(ks []int, dist []float64) []float64 {
+	weights := make([]float64, len(ks), len(ks))
+	sigma := dist[ks[len(ks)-1]]
+	sum := 0.0
+	for w, k := range ks {
+		weights[w] = 1
+		if sigma > 0 {
+			weights[w] = math.Exp(-dist[k] * dist[k] / (2 * sigma * sigma))
+		}
+		sum += weights[w]
+	}
+	for w := range weights {
+		weights[w] /= sum
+	}
+	return weights
+}
@@ -0,0 +1,56 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// lineSOM returns 1 by 5 map with nodes at 0, 0.25, ..., 1 predicting their position.
+func lineSOM() *SOM {
+	som := NewSOM(1, 5, 1, 1)
+	for _, node := range som.nodes {
+		node.fv[0] = float64(node.x) / 4
+		node.pv[0] = node.fv[0]
+	}
+	return som
+}
+
+// TestPredictInterpolated checks blending of two best matching nodes.
+func TestPredictInterpolated(t *testing.T) {
+	som := lineSOM()
+	if pv := som.Predict([]float64{0.1}); pv[0] != 0 {
+		t.Fatal("expected nearest node 0 got", pv)
+	}
+	pv, confidence := som.PredictInterpolated([]float64{0.1}, 2, InverseDistance)
+	if math.Abs(pv[0]-0.1) > 1e-12 {
+		t.Fatal("expected 0.1 got", pv)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		t.Fatal("expected confidence below 1 for different nodes got", confidence)
+	}
+
+	pv, _ = som.PredictInterpolated([]float64{0.1}, 2, GaussianKernel)
+	expected := 0.25 * math.Exp(-0.5) / (math.Exp(-0.01/0.045) + math.Exp(-0.5))
+	if math.Abs(pv[0]-expected) > 1e-12 {
+		t.Fatal("expected", expected, "got", pv)
+	}
+
+	// exact match takes all weight
+	if pv, _ := som.PredictInterpolated([]float64{0.5}, 3, InverseDistance); pv[0] != 0.5 {
+		t.Fatal("expected 0.5 got", pv)
+	}
+}
+
+// TestInterpolatedConfidence checks that agreeing nodes give full confidence.
+func TestInterpolatedConfidence(t *testing.T) {
+	som := lineSOM()
+	for _, node := range som.nodes {
+		node.pv[0] = 1
+	}
+	if _, confidence := som.PredictInterpolated([]float64{0.3}, 3, GaussianKernel); math.Abs(confidence-1) > 1e-12 {
+		t.Fatal("expected confidence 1 got", confidence)
+	}
+	if pv, confidence := som.PredictInterpolated([]float64{math.NaN()}, 3, InverseDistance); pv[0] != 0 || confidence != 0 {
+		t.Fatal("expected no prediction for NaN input got", pv, confidence)
+	}
+}
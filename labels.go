@@ -0,0 +1,132 @@
+// Artificial Neural Networks (ann) library in Go
+// Hit maps and node labels for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"sort"
+)
+
+// HitMap returns number of inputs fv matched by every node, indexed by row and column.
+func (som *SOM) HitMap(fv [][]float64) [][]int {
+	hits := make([][]int, som.height, som.height)
+	for y := range hits {
+		hits[y] = make([]int, som.width, som.width)
+	}
+	for _, input := range fv {
+		node := som.nodes[som.bestMatch(input)]
+		hits[node.y][node.x]++
+	}
+	return hits
+}
+
+// Samples returns indexes of inputs fv matched by node at column x and row y.
+func (som *SOM) Samples(fv [][]float64, x, y int) []int {
+	som.checkNode(x, y)
+	res := []int{}
+	for i, input := range fv {
+		if som.bestMatch(input) == y*som.width+x {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// NodeLabel holds labels of inputs matched by the node.
+type NodeLabel struct {
+	Label  string         // majority label, empty if node matched no inputs
+	Purity float64        // share of matched inputs with majority label
+	Hits   int            // number of matched inputs
+	Counts map[string]int // number of matched inputs per label
+}
+
+// Distribution returns share of matched inputs per label.
+func (l *NodeLabel) Distribution() map[string]float64 {
+	res := make(map[string]float64, len(l.Counts))
+	for label, count := range l.Counts {
+		res[label] = float64(count) / float64(l.Hits)
+	}
+	return res
+}
+
+// Label labels every node by majority vote of labels of inputs fv it matches,
+// ties go to the label first in sort order. Label again after training.
+func (som *SOM) Label(fv [][]float64, labels []string) {
+	if len(fv) != len(labels) {
+		panic("length of fv should match labels")
+	}
+	som.labels = make([]*NodeLabel, som.total, som.total)
+	for k := range som.labels {
+		som.labels[k] = &NodeLabel{Counts: map[string]int{}}
+	}
+	for i, input := range fv {
+		l := som.labels[som.bestMatch(input)]
+		l.Counts[labels[i]]++
+		l.Hits++
+	}
+
+	for _, l := range som.labels {
+		names := make([]string, 0, len(l.Counts))
+		for label := range l.Counts {
+			names = append(names, label)
+		}
+		sort.Strings(names)
+		majority := 0
+		for _, label := range names {
+			if l.Counts[label] > majority {
+				l.Label, majority = label, l.Counts[label]
+			}
+		}
+		if l.Hits > 0 {
+			l.Purity = float64(majority) / float64(l.Hits)
+		}
+	}
+}
+
+// NodeLabels returns copies of labels set by Label indexed by row and column,
+// nil before Label.
+func (som *SOM) NodeLabels() [][]*NodeLabel {
+	if som.labels == nil {
+		return nil
+	}
+	res := make([][]*NodeLabel, som.height, som.height)
+	for y := range res {
+		res[y] = make([]*NodeLabel, som.width, som.width)
+		for x, l := range som.labels[y*som.width : (y+1)*som.width] {
+			c := *l
+			c.Counts = make(map[string]int, len(l.Counts))
+			for label, count := range l.Counts {
+				c.Counts[label] = count
+			}
+			res[y][x] = &c
+		}
+	}
+	return res
+}
+
+// PredictLabel returns label of the best matching labeled node for fv and its purity
+// as confidence. Nodes that matched no inputs are skipped.
+func (som *SOM) PredictLabel(fv []float64) (label string, confidence float64) {
+	if som.labels == nil {
+		panic("map has no labels, call Label first")
+	}
+	if l := som.labels[som.bestMatch(fv)]; l.Hits > 0 {
+		return l.Label, l.Purity
+	}
+
+	best := -1
+	minimum := math.Inf(1)
+	for k, l := range som.labels {
+		if l.Hits == 0 {
+			continue
+		}
+		if temp := som.fvDistance(som.nodes[k].fv, fv); temp < minimum {
+			best, minimum = k, temp
+		}
+	}
+	if best < 0 {
+		return "", 0
+	}
+	return som.labels[best].Label, som.labels[best].Purity
+}
@@ -0,0 +1,54 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestHitMap checks hit counts and samples of the nodes.
+func TestHitMap(t *testing.T) {
+	som := gridSOM()
+	fv := [][]float64{{1, 1}, {29, 21}, {9, 2}, {0, -3}}
+	hits := som.HitMap(fv)
+	if hits[0][0] != 2 || hits[0][1] != 1 || hits[2][3] != 1 || hits[1][1] != 0 {
+		t.Fatal("unexpected hit map", hits)
+	}
+	if s := som.Samples(fv, 0, 0); len(s) != 2 || s[0] != 0 || s[1] != 3 {
+		t.Fatal("expected samples 0 and 3 got", s)
+	}
+	if s := som.Samples(fv, 1, 1); len(s) != 0 {
+		t.Fatal("expected no samples got", s)
+	}
+}
+
+// TestLabel checks majority labels, purity and label prediction.
+func TestLabel(t *testing.T) {
+	som := gridSOM()
+	fv := [][]float64{{1, 1}, {2, 0}, {0, 2}, {29, 21}, {30, 19}}
+	som.Label(fv, []string{"a", "b", "a", "c", "c"})
+
+	labels := som.NodeLabels()
+	l := labels[0][0]
+	if l.Label != "a" || l.Hits != 3 || math.Abs(l.Purity-2.0/3) > 1e-12 {
+		t.Fatal("unexpected node label", l)
+	}
+	if d := l.Distribution(); math.Abs(d["b"]-1.0/3) > 1e-12 {
+		t.Fatal("unexpected distribution", d)
+	}
+	if labels[1][1].Hits != 0 || labels[1][1].Label != "" {
+		t.Fatal("expected empty label got", labels[1][1])
+	}
+	// labels are copies
+	l.Label, l.Counts["a"] = "x", 10
+	if l := som.NodeLabels()[0][0]; l.Label != "a" || l.Counts["a"] != 2 {
+		t.Fatal("expected label unchanged got", l)
+	}
+
+	if label, conf := som.PredictLabel([]float64{28, 20}); label != "c" || conf != 1 {
+		t.Fatal("expected c with confidence 1 got", label, conf)
+	}
+	// node 1, 0 has no label, node 0, 0 is the closest labeled node
+	if label, _ := som.PredictLabel([]float64{11, 0}); label != "a" {
+		t.Fatal("expected a got", label)
+	}
+}
@@ -0,0 +1,305 @@
+// Artificial Neural Networks (ann) library in Go
+// Learning Vector Quantization - supervised classifier with labeled prototypes
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// LVQVariant is training rule of LVQ.
+type LVQVariant int
+
+const (
+	// LVQ1 moves best matching prototype towards input of its class and away from others.
+	LVQ1 LVQVariant = iota
+	// LVQ21 updates two closest prototypes when one of them has the input class, other
+	// has not and input falls into window between them.
+	LVQ21
+	// LVQ3 is LVQ21 that also moves both closest prototypes towards input when both
+	// have its class, by learning rate scaled by epsilon.
+	LVQ3
+	// GLVQ generalized LVQ of Sato and Yamada, minimizes sigmoid of relative distance
+	// difference between closest prototypes of correct and wrong class.
+	// Uses squared Euclidean distance for updates.
+	GLVQ
+)
+
+// String returns variant name.
+func (v LVQVariant) String() string {
+	switch v {
+	case LVQ1:
+		return "LVQ1"
+	case LVQ21:
+		return "LVQ2.1"
+	case LVQ3:
+		return "LVQ3"
+	case GLVQ:
+		return "GLVQ"
+	}
+	return "unknown"
+}
+
+// LVQ classifier with prototypes labeled by classes 0, 1, ...
+type LVQ struct {
+	prototypes   [][]float64
+	classes      []int // class of every prototype
+	fvSize       int
+	variant      LVQVariant
+	learningRate Schedule
+	window       float64 // relative window width of LVQ21 and LVQ3
+	epsilon      float64 // LVQ3 rate scale for prototypes of the same class
+	metric       Metric
+	initPending  bool // prototypes are set from inputs at the start of Train
+}
+
+// NewLVQ creates LVQ classifier with counts[c] prototypes of class c. Prototypes are
+// set to random inputs of their class at the start of the first Train.
+func NewLVQ(variant LVQVariant, fvSize int, counts []int) *LVQ {
+	l := newLVQ(variant, fvSize)
+	for c, count := range counts {
+		for i := 0; i < count; i++ {
+			fv := make([]float64, fvSize, fvSize)
+			for m := range fv {
+				fv[m] = rand.Float64()
+			}
+			l.prototypes = append(l.prototypes, fv)
+			l.classes = append(l.classes, c)
+		}
+	}
+	l.initPending = true
+	return l
+}
+
+// NewLVQFromSOM creates LVQ classifier with prototypes from nodes of trained map,
+// every node is labeled by majority class of inputs fv it matches, ties go to the lower
+// class. Nodes that match no inputs are left out.
+func NewLVQFromSOM(variant LVQVariant, som *SOM, fv [][]float64, classes []int) *LVQ {
+	if len(fv) != len(classes) {
+		panic("length of fv should match classes")
+	}
+	checkClassLabels(classes)
+	votes := make([]map[int]int, som.total, som.total)
+	for i, input := range fv {
+		best := som.bestMatch(input)
+		if votes[best] == nil {
+			votes[best] = map[int]int{}
+		}
+		votes[best][classes[i]]++
+	}
+
+	l := newLVQ(variant, som.fvSize)
+	l.metric = som.metric
+	for k, node := range som.nodes {
+		if votes[k] == nil {
+			continue
+		}
+		class, majority := 0, 0
+		for c, count := range votes[k] {
+			if count > majority || count == majority && c < class {
+				class, majority = c, count
+			}
+		}
+		l.prototypes = append(l.prototypes, copyVector(node.fv))
+		l.classes = append(l.classes, class)
+	}
+	return l
+}
+
+// newLVQ creates classifier without prototypes.
+func newLVQ(variant LVQVariant, fvSize int) *LVQ {
+	return &LVQ{
+		fvSize:       fvSize,
+		variant:      variant,
+		learningRate: Schedule{Initial: 0.1, Final: 0, Decay: Linear},
+		window:       0.3,
+		epsilon:      0.2,
+		metric:       Euclidean{},
+	}
+}
+
+// SetLearningRate sets schedule of the learning rate, default decays linearly
+// from 0.1 to 0. Schedule runs over all inputs of all iterations.
+func (l *LVQ) SetLearningRate(learningRate Schedule) {
+	learningRate.check()
+	l.learningRate = learningRate
+}
+
+// SetWindow sets relative window width of LVQ21 and LVQ3 and epsilon of LVQ3,
+// defaults are 0.3 and 0.2.
+func (l *LVQ) SetWindow(window, epsilon float64) {
+	l.window = window
+	l.epsilon = epsilon
+}
+
+// SetMetric sets distance metric used to find closest prototypes, default is Euclidean.
+func (l *LVQ) SetMetric(metric Metric) {
+	checkMetric(metric, l.fvSize)
+	l.metric = metric
+}
+
+// Train performs LVQ training for specified number of iterations over inputs fv of classes.
+func (l *LVQ) Train(iterations int, fv [][]float64, classes []int) {
+	if len(fv) != len(classes) {
+		panic("length of fv should match classes")
+	}
+	checkClassLabels(classes)
+	if l.initPending {
+		l.initialize(fv, classes)
+	}
+
+	steps := iterations * len(fv)
+	step := 0
+	for i := 0; i < iterations; i++ {
+		for j, input := range fv {
+			step++
+			rate := l.learningRate.At(step, steps)
+			switch l.variant {
+			case LVQ1:
+				l.trainLVQ1(rate, input, classes[j])
+			case LVQ21, LVQ3:
+				l.trainLVQ2(rate, input, classes[j])
+			case GLVQ:
+				l.trainGLVQ(rate, input, classes[j])
+			}
+		}
+	}
+}
+
+// checkClassLabels panics on negative class of any input.
+func checkClassLabels(classes []int) {
+	for i, c := range classes {
+		if c < 0 {
+			panic(fmt.Sprintf("class %d of input %d should not be negative", c, i))
+		}
+	}
+}
+
+// initialize sets every prototype to random input of its class.
+func (l *LVQ) initialize(fv [][]float64, classes []int) {
+	l.initPending = false
+	byClass := map[int][]int{}
+	for i, c := range classes {
+		byClass[c] = append(byClass[c], i)
+	}
+	for k, c := range l.classes {
+		if inputs := byClass[c]; len(inputs) > 0 {
+			copy(l.prototypes[k], fv[inputs[rand.Intn(len(inputs))]])
+		}
+	}
+}
+
+// trainLVQ1 updates closest prototype.
+func (l *LVQ) trainLVQ1(rate float64, fv []float64, class int) {
+	best, _, _, _ := l.matches(fv)
+	if best < 0 {
+		return
+	}
+	if l.classes[best] != class {
+		rate = -rate
+	}
+	moveTowards(l.prototypes[best], rate, fv)
+}
+
+// trainLVQ2 updates two closest prototypes by LVQ2.1 or LVQ3 rule.
+func (l *LVQ) trainLVQ2(rate float64, fv []float64, class int) {
+	i, j, di, dj := l.matches(fv)
+	if i < 0 || j < 0 {
+		return
+	}
+	ci, cj := l.classes[i] == class, l.classes[j] == class
+	switch {
+	case ci != cj:
+		// input is in window when min(di/dj, dj/di) > s, compared without
+		// division so that input on both prototypes is outside
+		s := (1 - l.window) / (1 + l.window)
+		if math.Min(di, dj) <= s*math.Max(di, dj) {
+			return
+		}
+		if !ci {
+			i, j = j, i
+		}
+		moveTowards(l.prototypes[i], rate, fv)
+		moveTowards(l.prototypes[j], -rate, fv)
+	case ci && l.variant == LVQ3:
+		moveTowards(l.prototypes[i], l.epsilon*rate, fv)
+		moveTowards(l.prototypes[j], l.epsilon*rate, fv)
+	}
+}
+
+// trainGLVQ updates closest prototypes of correct and wrong class.
+func (l *LVQ) trainGLVQ(rate float64, fv []float64, class int) {
+	plus, minus := -1, -1
+	dPlus, dMinus := math.Inf(1), math.Inf(1)
+	for k, p := range l.prototypes {
+		d := SquaredEuclidean{}.Distance(p, fv[:l.fvSize])
+		if l.classes[k] == class && d < dPlus {
+			plus, dPlus = k, d
+		} else if l.classes[k] != class && d < dMinus {
+			minus, dMinus = k, d
+		}
+	}
+	if plus < 0 || minus < 0 || dPlus+dMinus == 0 {
+		return
+	}
+
+	mu := (dPlus - dMinus) / (dPlus + dMinus)
+	f := sigmoid(mu)
+	scale := rate * f * (1 - f) * 4 / ((dPlus + dMinus) * (dPlus + dMinus))
+	moveTowards(l.prototypes[plus], scale*dMinus, fv)
+	moveTowards(l.prototypes[minus], -scale*dPlus, fv)
+}
+
+// moveTowards moves prototype p towards fv by rate, negative rate moves it away.
+func moveTowards(p []float64, rate float64, fv []float64) {
+	for m := range p {
+		p[m] += rate * (fv[m] - p[m])
+	}
+}
+
+// matches finds two closest prototypes, missing prototypes are -1.
+func (l *LVQ) matches(fv []float64) (best, second int, bestDist, secondDist float64) {
+	s := newMatchSet()
+	for k, p := range l.prototypes {
+		s.add(k, l.metric.Distance(p, fv[:l.fvSize]))
+	}
+	return s.best, s.second, s.bestDist, s.secondDist
+}
+
+// PredictClass returns class of the closest prototype, -1 if there are no prototypes
+// or all distances are NaN.
+func (l *LVQ) PredictClass(fv []float64) int {
+	best, _, _, _ := l.matches(fv)
+	if best < 0 {
+		return -1
+	}
+	return l.classes[best]
+}
+
+// Counts returns number of prototypes of every class.
+func (l *LVQ) Counts() []int {
+	res := []int{}
+	for _, c := range l.classes {
+		for len(res) <= c {
+			res = append(res, 0)
+		}
+		res[c]++
+	}
+	return res
+}
+
+// Codebook returns copies of prototypes.
+func (l *LVQ) Codebook() [][]float64 {
+	res := make([][]float64, len(l.prototypes), len(l.prototypes))
+	for k, p := range l.prototypes {
+		res[k] = copyVector(p)
+	}
+	return res
+}
+
+// Classes returns class of every prototype.
+func (l *LVQ) Classes() []int {
+	return append([]int{}, l.classes...)
+}
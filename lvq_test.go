@@ -0,0 +1,93 @@
+package ann
+
+import "testing"
+
+// xorData returns clusterData inputs with classes of XOR problem,
+// which no single prototype per class can solve.
+func xorData() (fv [][]float64, classes []int) {
+	fv, _ = clusterData()
+	for _, input := range fv {
+		classes = append(classes, int(input[0]+0.5)^int(input[1]+0.5))
+	}
+	return fv, classes
+}
+
+// checkClasses checks that classifier predicts class of every input.
+func checkClasses(t *testing.T, l *LVQ, fv [][]float64, classes []int) {
+	for i, input := range fv {
+		if c := l.PredictClass(input); c != classes[i] {
+			t.Fatal(l.variant, "expected class", classes[i], "for", input, "got", c)
+		}
+	}
+}
+
+// TestLVQ trains every LVQ variant on XOR problem.
+func TestLVQ(t *testing.T) {
+	fv, classes := xorData()
+	for _, variant := range []LVQVariant{LVQ1, LVQ21, LVQ3, GLVQ} {
+		// prototype per cluster, initialized at its cluster
+		l := newLVQ(variant, 2)
+		l.prototypes = [][]float64{{0.2, 0.1}, {0.9, 0.8}, {0.9, 0.1}, {0.2, 0.8}}
+		l.classes = []int{0, 0, 1, 1}
+		l.Train(20, fv, classes)
+		checkClasses(t, l, fv, classes)
+	}
+
+	l := NewLVQ(LVQ1, 2, []int{3, 2})
+	if c := l.Counts(); len(c) != 2 || c[0] != 3 || c[1] != 2 {
+		t.Fatal("unexpected prototype counts", c)
+	}
+	if len(l.Codebook()) != 5 || l.Classes()[4] != 1 {
+		t.Fatal("unexpected prototypes", l.Codebook(), l.Classes())
+	}
+
+	// prototypes start at inputs of their class, left and right side are separable
+	sides := make([]int, len(fv), len(fv))
+	for i, input := range fv {
+		sides[i] = int(input[0] + 0.5)
+	}
+	l.Train(20, fv, sides)
+	checkClasses(t, l, fv, sides)
+}
+
+// TestLVQFromSOM labels nodes of trained map and fine tunes them.
+func TestLVQFromSOM(t *testing.T) {
+	fv, classes := xorData()
+	pv := make([][]float64, len(fv), len(fv))
+	for i := range pv {
+		pv[i] = []float64{}
+	}
+	som := NewSOM(4, 4, 2, 0)
+	som.SetInitialization(LinearInit)
+	som.TrainBatch(20, fv, pv)
+
+	l := NewLVQFromSOM(GLVQ, som, fv, classes)
+	counts := l.Counts()
+	if len(counts) != 2 || counts[0] == 0 || counts[1] == 0 || counts[0]+counts[1] > 16 {
+		t.Fatal("unexpected prototype counts", counts)
+	}
+	l.Train(10, fv, classes)
+	checkClasses(t, l, fv, classes)
+}
+
+// TestLVQWindow checks that input at zero distance from two prototypes of different
+// classes is outside of the window and negative classes are rejected.
+func TestLVQWindow(t *testing.T) {
+	l := NewLVQ(LVQ21, 2, []int{1, 1})
+	l.SetMetric(Cosine{})
+	l.SetLearningRate(Schedule{Initial: 0.1, Final: 0.1, Decay: Linear})
+	l.initPending = false
+	l.prototypes[0] = []float64{1, 0}
+	l.prototypes[1] = []float64{2, 0}
+	l.Train(1, [][]float64{{3, 0}}, []int{0})
+	if p := l.Codebook(); p[0][0] != 1 || p[1][0] != 2 {
+		t.Fatal("expected prototypes unchanged got", p)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Train to panic on negative class")
+		}
+	}()
+	l.Train(1, [][]float64{{3, 0}}, []int{-1})
+}
@@ -0,0 +1,72 @@
+// Artificial Neural Networks (ann) library in Go
+// Dense matrix kernels used by Backprop
+// Matrices are stored as flat row-major []float64 slices.
+// released under MIT license
+package ann
+
+import "github.com/tadvi/ann/internal/kernel"
+
+// blockSize is number of matrix rows processed at once by matMul,
+// block of the right hand side matrix should stay in cache while it is reused.
+const blockSize = 64
+
+// vecMat calculates y = x * w, where w has len(x) rows and len(y) columns.
+func vecMat(y, x, w []float64) {
+	for j := range y {
+		y[j] = 0
+	}
+	vecMatAdd(y, x, w)
+}
+
+// vecMatAdd calculates y += x * w, where w has len(x) rows and len(y) columns.
+func vecMatAdd(y, x, w []float64) {
+	cols := len(y)
+	// four rows at a time, so y is loaded and stored once per four rows
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		x0, x1, x2, x3 := x[i], x[i+1], x[i+2], x[i+3]
+		r0 := w[i*cols : (i+1)*cols]
+		r1 := w[(i+1)*cols : (i+2)*cols]
+		r2 := w[(i+2)*cols : (i+3)*cols]
+		r3 := w[(i+3)*cols : (i+4)*cols]
+		for j := range y {
+			y[j] += x0*r0[j] + x1*r1[j] + x2*r2[j] + x3*r3[j]
+		}
+	}
+	for ; i < len(x); i++ {
+		kernel.Axpy(x[i], w[i*cols:(i+1)*cols], y)
+	}
+}
+
+// matVec calculates y = w * x, where w has len(y) rows and len(x) columns.
+func matVec(y, w, x []float64) {
+	cols := len(x)
+	for i := range y {
+		y[i] = kernel.Dot(x, w[i*cols:(i+1)*cols])
+	}
+}
+
+// addOuter calculates w += a * x * transpose(y), where w has len(x) rows and len(y) columns.
+func addOuter(w []float64, a float64, x, y []float64) {
+	cols := len(y)
+	for i, v := range x {
+		kernel.Axpy(a*v, y, w[i*cols:(i+1)*cols])
+	}
+}
+
+// matMul calculates c = a * b, where a is m by k and b is k by n matrix.
+func matMul(c, a, b []float64, m, k, n int) {
+	c = c[:m*n]
+	for i := range c {
+		c[i] = 0
+	}
+	for kk := 0; kk < k; kk += blockSize {
+		kEnd := kk + blockSize
+		if kEnd > k {
+			kEnd = k
+		}
+		for i := 0; i < m; i++ {
+			vecMatAdd(c[i*n:(i+1)*n], a[i*k+kk:i*k+kEnd], b[kk*n:kEnd*n])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Artificial Neural Networks (ann) library in Go
+// Dense float32 matrix kernels used by Backprop32
+// Matrices are stored as flat row-major []float32 slices.
+// released under MIT license
+package ann
+
+// dot32 calculates dot product of two vectors of the same length.
+func dot32(x, y []float32) float32 {
+	y = y[:len(x)]
+	var s0, s1, s2, s3 float32
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		s0 += x[i] * y[i]
+		s1 += x[i+1] * y[i+1]
+		s2 += x[i+2] * y[i+2]
+		s3 += x[i+3] * y[i+3]
+	}
+	for ; i < len(x); i++ {
+		s0 += x[i] * y[i]
+	}
+	return (s0 + s1) + (s2 + s3)
+}
+
+// axpy32 calculates y += a * x.
+func axpy32(a float32, x, y []float32) {
+	y = y[:len(x)]
+	for i, v := range x {
+		y[i] += a * v
+	}
+}
+
+// vecMat32 calculates y = x * w, where w has len(x) rows and len(y) columns.
+func vecMat32(y, x, w []float32) {
+	for j := range y {
+		y[j] = 0
+	}
+	vecMatAdd32(y, x, w)
+}
+
+// vecMatAdd32 calculates y += x * w, where w has len(x) rows and len(y) columns.
+func vecMatAdd32(y, x, w []float32) {
+	cols := len(y)
+	// four rows at a time, so y is loaded and stored once per four rows
+	i := 0
+	for ; i+4 <= len(x); i += 4 {
+		x0, x1, x2, x3 := x[i], x[i+1], x[i+2], x[i+3]
+		r0 := w[i*cols : (i+1)*cols]
+		r1 := w[(i+1)*cols : (i+2)*cols]
+		r2 := w[(i+2)*cols : (i+3)*cols]
+		r3 := w[(i+3)*cols : (i+4)*cols]
+		for j := range y {
+			y[j] += x0*r0[j] + x1*r1[j] + x2*r2[j] + x3*r3[j]
+		}
+	}
+	for ; i < len(x); i++ {
+		axpy32(x[i], w[i*cols:(i+1)*cols], y)
+	}
+}
+
+// matVec32 calculates y = w * x, where w has len(y) rows and len(x) columns.
+func matVec32(y, w, x []float32) {
+	cols := len(x)
+	for i := range y {
+		y[i] = dot32(x, w[i*cols:(i+1)*cols])
+	}
+}
+
+// addOuter32 calculates w += a * x * transpose(y), where w has len(x) rows and len(y) columns.
+func addOuter32(w []float32, a float32, x, y []float32) {
+	cols := len(y)
+	for i, v := range x {
+		axpy32(a*v, y, w[i*cols:(i+1)*cols])
+	}
+}
@@ -0,0 +1,59 @@
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomSlice creates slice of random values in range [-1, 1).
+func randomSlice(size int) []float64 {
+	s := make([]float64, size, size)
+	for i := range s {
+		s[i] = 2*rand.Float64() - 1
+	}
+	return s
+}
+
+// TestMatMul compares blocked matrix kernels with naive loops.
+func TestMatMul(t *testing.T) {
+	for _, dims := range [][3]int{{1, 1, 1}, {3, 5, 2}, {7, 130, 9}, {65, 3, 70}} {
+		m, k, n := dims[0], dims[1], dims[2]
+		a := randomSlice(m * k)
+		b := randomSlice(k * n)
+		c := make([]float64, m*n, m*n)
+		matMul(c, a, b, m, k, n)
+
+		for i := 0; i < m; i++ {
+			// row of the result is same as vector matrix product
+			row := make([]float64, n, n)
+			vecMat(row, a[i*k:(i+1)*k], b)
+			for j := 0; j < n; j++ {
+				expected := 0.0
+				for p := 0; p < k; p++ {
+					expected += a[i*k+p] * b[p*n+j]
+				}
+				if math.Abs(c[i*n+j]-expected) > 1e-9 || math.Abs(row[j]-expected) > 1e-9 {
+					t.Fatal("expected", expected, "got", c[i*n+j], row[j], "for", dims)
+				}
+			}
+		}
+	}
+}
+
+// TestMatVec compares matrix vector product with naive loops.
+func TestMatVec(t *testing.T) {
+	w := randomSlice(6 * 11)
+	x := randomSlice(11)
+	y := make([]float64, 6, 6)
+	matVec(y, w, x)
+	for i := range y {
+		expected := 0.0
+		for j := range x {
+			expected += w[i*11+j] * x[j]
+		}
+		if math.Abs(y[i]-expected) > 1e-9 {
+			t.Fatal("expected", expected, "got", y[i])
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Artificial Neural Networks (ann) library in Go
+// Distance metrics for feature vectors
+// released under MIT license
+package ann
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/tadvi/ann/internal/kernel"
+)
+
+// Metric calculates distance between two feature vectors of the same length.
+type Metric interface {
+	Distance(fv1, fv2 []float64) float64
+}
+
+// Euclidean distance, default SOM metric.
+type Euclidean struct{}
+
+// Distance implements Metric.
+func (Euclidean) Distance(fv1, fv2 []float64) float64 {
+	return math.Sqrt(kernel.SqDist(fv1, fv2))
+}
+
+// SquaredEuclidean distance, same best matches as Euclidean without square root.
+type SquaredEuclidean struct{}
+
+// Distance implements Metric.
+func (SquaredEuclidean) Distance(fv1, fv2 []float64) float64 {
+	return kernel.SqDist(fv1, fv2)
+}
+
+// Manhattan distance, sum of absolute differences.
+type Manhattan struct{}
+
+// Distance implements Metric.
+func (Manhattan) Distance(fv1, fv2 []float64) float64 {
+	temp := 0.0
+	for i, v := range fv1 {
+		temp += math.Abs(v - fv2[i])
+	}
+	return temp
+}
+
+// Chebyshev distance, largest absolute difference.
+type Chebyshev struct{}
+
+// Distance implements Metric.
+func (Chebyshev) Distance(fv1, fv2 []float64) float64 {
+	temp := 0.0
+	for i, v := range fv1 {
+		temp = math.Max(temp, math.Abs(v-fv2[i]))
+	}
+	return temp
+}
+
+// Cosine distance 1 - cos(angle) in range [0, 2], zero vector is at distance 1 from any vector.
+type Cosine struct{}
+
+// Distance implements Metric.
+func (Cosine) Distance(fv1, fv2 []float64) float64 {
+	norm := math.Sqrt(kernel.Dot(fv1, fv1) * kernel.Dot(fv2[:len(fv1)], fv2))
+	if norm == 0 {
+		return 1
+	}
+	return 1 - kernel.Dot(fv1, fv2)/norm
+}
+
+// Mahalanobis distance for covariance matrix of the features.
+type Mahalanobis struct {
+	size       int
+	covariance [][]float64
+	whiten     []float64 // inverse of lower Cholesky factor of covariance matrix, row-major
+}
+
+// NewMahalanobis creates Mahalanobis metric from symmetric positive-definite covariance matrix.
+func NewMahalanobis(covariance [][]float64) (*Mahalanobis, error) {
+	lower, err := cholesky(covariance)
+	if err != nil {
+		return nil, err
+	}
+	n := len(covariance)
+	m := &Mahalanobis{size: n, covariance: make([][]float64, n, n), whiten: invertLower(lower, n)}
+	for i, row := range covariance {
+		m.covariance[i] = copyVector(row)
+	}
+	return m, nil
+}
+
+// block returns metric for features [lo, hi) from block of the covariance matrix,
+// which is positive-definite as the whole matrix.
+func (m *Mahalanobis) block(lo, hi int) *Mahalanobis {
+	covariance := make([][]float64, hi-lo, hi-lo)
+	for i := range covariance {
+		covariance[i] = m.covariance[lo+i][lo:hi]
+	}
+	res, err := NewMahalanobis(covariance)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// Distance implements Metric. Distance is length of difference whitened by inverse
+// Cholesky factor, so it is never negative.
+func (m *Mahalanobis) Distance(fv1, fv2 []float64) float64 {
+	temp := 0.0
+	for i := 0; i < m.size; i++ {
+		row := m.whiten[i*m.size : i*m.size+i+1]
+		s := 0.0
+		for j, v := range row {
+			s += v * (fv1[j] - fv2[j])
+		}
+		temp += s * s
+	}
+	return math.Sqrt(temp)
+}
+
+// checkMetric panics when Mahalanobis metric was created for different number of features.
+func checkMetric(metric Metric, fvSize int) {
+	if m, ok := metric.(*Mahalanobis); ok && m.size != fvSize {
+		panic(fmt.Sprintf("Mahalanobis metric size %d should match feature vector size %d", m.size, fvSize))
+	}
+}
+
+// rangeMetric returns metric for features [lo, hi) of vectors metric was set for,
+// Mahalanobis metric is restricted to block of its covariance matrix.
+func rangeMetric(metric Metric, lo, hi int) Metric {
+	if m, ok := metric.(*Mahalanobis); ok && (lo != 0 || hi != m.size) {
+		return m.block(lo, hi)
+	}
+	return metric
+}
+
+// cholesky returns row-major lower triangular factor L of symmetric positive-definite
+// matrix A = L·Lᵀ.
+func cholesky(matrix [][]float64) ([]float64, error) {
+	n := len(matrix)
+	if n == 0 {
+		return nil, errors.New("matrix is empty")
+	}
+	for i, row := range matrix {
+		if len(row) != n {
+			return nil, errors.New("matrix is not square")
+		}
+		for j := 0; j < i; j++ {
+			if math.Abs(row[j]-matrix[j][i]) > 1e-9*math.Max(math.Abs(row[j]), math.Abs(matrix[j][i])) {
+				return nil, errors.New("matrix is not symmetric")
+			}
+		}
+	}
+
+	lower := make([]float64, n*n, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			s := matrix[i][j] - kernel.Dot(lower[i*n:i*n+j], lower[j*n:j*n+j])
+			if i != j {
+				lower[i*n+j] = s / lower[j*n+j]
+				continue
+			}
+			// pivot relative to the diagonal value catches singular matrices
+			if !(s > 1e-12*math.Abs(matrix[i][i])) {
+				return nil, errors.New("matrix is not positive definite")
+			}
+			lower[i*n+i] = math.Sqrt(s)
+		}
+	}
+	return lower, nil
+}
+
+// invertLower returns row-major inverse of row-major lower triangular matrix of size n.
+func invertLower(lower []float64, n int) []float64 {
+	inverse := make([]float64, n*n, n*n)
+	for i := 0; i < n; i++ {
+		inverse[i*n+i] = 1 / lower[i*n+i]
+		for j := 0; j < i; j++ {
+			s := 0.0
+			for k := j; k < i; k++ {
+				s += lower[i*n+k] * inverse[k*n+j]
+			}
+			inverse[i*n+j] = -s / lower[i*n+i]
+		}
+	}
+	return inverse
+}
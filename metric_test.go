@@ -0,0 +1,81 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestMetrics checks distances between two known vectors.
+func TestMetrics(t *testing.T) {
+	fv1 := []float64{1, 2, 3}
+	fv2 := []float64{4, 0, 3}
+	identity, err := NewMahalanobis([][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scaled, err := NewMahalanobis([][]float64{{9, 0, 0}, {0, 4, 0}, {0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		m        Metric
+		expected float64
+	}{
+		{Euclidean{}, math.Sqrt(13)},
+		{SquaredEuclidean{}, 13},
+		{Manhattan{}, 5},
+		{Chebyshev{}, 3},
+		{Cosine{}, 1 - 13/(math.Sqrt(14)*5)},
+		{identity, math.Sqrt(13)},
+		{scaled, math.Sqrt2},
+	}
+	for _, c := range cases {
+		if d := c.m.Distance(fv1, fv2); math.Abs(d-c.expected) > 1e-12 {
+			t.Fatalf("expected %T distance %v got %v", c.m, c.expected, d)
+		}
+	}
+	if d := (Cosine{}).Distance([]float64{0, 0}, []float64{1, 2}); d != 1 {
+		t.Fatal("expected cosine distance 1 for zero vector got", d)
+	}
+}
+
+// TestMahalanobisCovariance checks distance with correlated features.
+func TestMahalanobisCovariance(t *testing.T) {
+	m, err := NewMahalanobis([][]float64{{2, 1}, {1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// inverse is [[2, -1], [-1, 2]] / 3
+	if d := m.Distance([]float64{1, 1}, []float64{0, 0}); math.Abs(d-math.Sqrt(2.0/3)) > 1e-12 {
+		t.Fatal("expected", math.Sqrt(2.0/3), "got", d)
+	}
+	if _, err := NewMahalanobis([][]float64{{1, 2}, {2, 4}}); err == nil {
+		t.Fatal("expected error for singular covariance")
+	}
+	if _, err := NewMahalanobis([][]float64{{1, 2}, {2, 1}}); err == nil {
+		t.Fatal("expected error for covariance that is not positive definite")
+	}
+	if _, err := NewMahalanobis([][]float64{{2, 1}, {0, 2}}); err == nil {
+		t.Fatal("expected error for covariance that is not symmetric")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected SetMetric to panic on size mismatch")
+		}
+	}()
+	NewSOM(2, 2, 3, 1).SetMetric(m)
+}
+
+// TestSOMMetric trains map with Manhattan metric on basic patterns.
+func TestSOMMetric(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(12, 12, 10, 3)
+	som.SetMetric(Manhattan{})
+	som.Train(2000, data, result)
+	for i := range data {
+		if res := som.PredictInt(data[i]); res[i] < 85 {
+			t.Fatal("expected", i, "to be above 85% got", res)
+		}
+	}
+}
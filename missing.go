@@ -0,0 +1,35 @@
+// Artificial Neural Networks (ann) library in Go
+// Imputation of missing values with Self-Organizing Maps
+// released under MIT license
+package ann
+
+import "math"
+
+// Impute returns copy of fv with missing NaN values replaced by feature values
+// of the best matching node, which is found from values that are present.
+// Values stay NaN when all of them are missing.
+func (som *SOM) Impute(fv []float64) []float64 {
+	res := copyVector(fv[:som.fvSize])
+	if !hasNaN(res) {
+		return res
+	}
+	best, _, bestDist, _ := som.bestMatches(res)
+	if math.IsNaN(bestDist) {
+		return res
+	}
+	for m, v := range res {
+		if math.IsNaN(v) {
+			res[m] = som.nodes[best].fv[m]
+		}
+	}
+	return res
+}
+
+// ImputeAll returns copies of inputs fv with missing values imputed, see Impute.
+func (som *SOM) ImputeAll(fv [][]float64) [][]float64 {
+	res := make([][]float64, len(fv), len(fv))
+	for i, input := range fv {
+		res[i] = som.Impute(input)
+	}
+	return res
+}
@@ -0,0 +1,102 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// withMissing returns copy of inputs with value i%4 of every input i set to NaN.
+func withMissing(fv [][]float64) [][]float64 {
+	res := [][]float64{}
+	for i, input := range fv {
+		input = copyVector(input)
+		if m := i % 4; m < len(input) {
+			input[m] = math.NaN()
+		}
+		res = append(res, input)
+	}
+	return res
+}
+
+// checkNoNaN checks that training kept node vectors free of NaN.
+func checkNoNaN(t *testing.T, som *SOM) {
+	for _, node := range som.nodes {
+		if hasNaN(node.fv) || hasNaN(node.pv) {
+			t.Fatal("expected node without NaN got", node)
+		}
+	}
+}
+
+// TestMissingTrain trains maps on inputs with missing values.
+func TestMissingTrain(t *testing.T) {
+	data, result, _ := basicPatterns()
+	data = withMissing(append(append(append([][]float64{}, data...), data...), data...))
+	result = append(append(append([][]float64{}, result...), result...), result...)
+
+	som := NewSOM(12, 12, 10, 3)
+	som.Train(1000, data, result)
+	checkNoNaN(t, som)
+
+	batch := NewSOM(12, 12, 10, 3)
+	batch.TrainBatch(50, data, result)
+	checkNoNaN(t, batch)
+
+	// prediction skips missing values
+	for _, s := range []*SOM{som, batch} {
+		for i, fv := range data {
+			if res := s.Predict(fv); argmax(res) != i%3 {
+				t.Fatal("expected", i%3, "for", fv, "got", res)
+			}
+		}
+	}
+}
+
+// TestImpute checks that missing values come from the best matching node.
+func TestImpute(t *testing.T) {
+	som := gridSOM()
+	fv := []float64{math.NaN(), 19}
+	res := som.Impute(fv)
+	if res[0] != 0 || res[1] != 19 || !math.IsNaN(fv[0]) {
+		t.Fatal("expected 0, 19 with input unchanged got", res, fv)
+	}
+
+	all := som.ImputeAll([][]float64{{21, math.NaN()}, {math.NaN(), math.NaN()}, {1, 2}})
+	if all[0][0] != 21 || all[0][1] != 0 || !math.IsNaN(all[1][0]) || all[2][1] != 2 {
+		t.Fatal("unexpected imputed inputs", all)
+	}
+}
+
+// TestMaskedDistance checks that distance over present values is scaled to all values.
+func TestMaskedDistance(t *testing.T) {
+	zero := []float64{0, 0, 0, 0}
+	ones := []float64{1, 1, 1, 1}
+	partial := []float64{1, math.NaN(), 1, math.NaN()}
+	for _, metric := range []Metric{Euclidean{}, SquaredEuclidean{}, Manhattan{}, Chebyshev{}} {
+		expected := metric.Distance(zero, ones)
+		if dist := metricDistance(metric, zero, partial); math.Abs(dist-expected) > 1e-12 {
+			t.Fatal("expected", expected, "for", metric, "got", dist)
+		}
+	}
+
+	m, err := NewMahalanobis([][]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Mahalanobis to panic on missing values")
+		}
+	}()
+	metricDistance(m, zero, partial)
+}
+
+// argmax returns index of the largest value.
+func argmax(values []float64) int {
+	best := 0
+	for i, v := range values {
+		if v > values[best] {
+			best = i
+		}
+	}
+	return best
+}
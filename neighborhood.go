@@ -0,0 +1,80 @@
+// Artificial Neural Networks (ann) library in Go
+// Neighborhood functions for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"math"
+)
+
+// Neighborhood calculates how much node at grid distance dist from the best
+// matching node learns from the input, radius is current neighborhood radius.
+// Nodes with zero influence are not updated.
+type Neighborhood interface {
+	Influence(dist, radius float64) float64
+}
+
+// Gaussian neighborhood exp(-d²/2r²), every node learns.
+type Gaussian struct{}
+
+// Influence implements Neighborhood.
+func (Gaussian) Influence(dist, radius float64) float64 {
+	if radius <= 0 {
+		return unit(dist)
+	}
+	return math.Exp(-dist * dist / (2 * radius * radius))
+}
+
+// CutGaussian Gaussian neighborhood cut off at the radius.
+type CutGaussian struct{}
+
+// Influence implements Neighborhood.
+func (CutGaussian) Influence(dist, radius float64) float64 {
+	if dist > radius {
+		return 0
+	}
+	return Gaussian{}.Influence(dist, radius)
+}
+
+// Bubble neighborhood, all nodes within the radius learn equally.
+type Bubble struct{}
+
+// Influence implements Neighborhood.
+func (Bubble) Influence(dist, radius float64) float64 {
+	if dist > radius {
+		return 0
+	}
+	return 1
+}
+
+// MexicanHat neighborhood (1 - d²/r²) exp(-d²/2r²), nodes beyond the radius
+// are pushed away from the input.
+type MexicanHat struct{}
+
+// Influence implements Neighborhood.
+func (MexicanHat) Influence(dist, radius float64) float64 {
+	if radius <= 0 {
+		return unit(dist)
+	}
+	d2 := dist * dist / (radius * radius)
+	return (1 - d2) * math.Exp(-d2/2)
+}
+
+// Triangular neighborhood 1 - d/r falling linearly to zero at the radius.
+type Triangular struct{}
+
+// Influence implements Neighborhood.
+func (Triangular) Influence(dist, radius float64) float64 {
+	if dist >= radius {
+		return unit(dist)
+	}
+	return 1 - dist/radius
+}
+
+// unit returns influence when neighborhood shrinks to the best matching node only.
+func unit(dist float64) float64 {
+	if dist == 0 {
+		return 1
+	}
+	return 0
+}
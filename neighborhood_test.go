@@ -0,0 +1,58 @@
+package ann
+
+import (
+	"testing"
+)
+
+// TestNeighborhoodShape checks influence at the best matching node, inside and outside the radius.
+func TestNeighborhoodShape(t *testing.T) {
+	cases := []struct {
+		n              Neighborhood
+		inside, beyond func(float64) bool
+	}{
+		{Gaussian{}, between(0.6, 0.61), positive},
+		{CutGaussian{}, between(0.6, 0.61), zero},
+		{Bubble{}, between(1, 1), zero},
+		{MexicanHat{}, between(0, 0), negative},
+		{Triangular{}, between(0, 0), zero},
+	}
+	for _, c := range cases {
+		if v := c.n.Influence(0, 2); v != 1 {
+			t.Fatalf("expected %T influence 1 at the best matching node got %v", c.n, v)
+		}
+		if v := c.n.Influence(0, 0); v != 1 {
+			t.Fatalf("expected %T influence 1 with zero radius got %v", c.n, v)
+		}
+		if v := c.n.Influence(2, 2); !c.inside(v) {
+			t.Fatalf("unexpected %T influence %v at the radius", c.n, v)
+		}
+		if v := c.n.Influence(3, 2); !c.beyond(v) {
+			t.Fatalf("unexpected %T influence %v beyond the radius", c.n, v)
+		}
+	}
+}
+
+func between(lo, hi float64) func(float64) bool {
+	return func(v float64) bool { return v >= lo && v <= hi }
+}
+
+func positive(v float64) bool { return v > 0 }
+func zero(v float64) bool     { return v == 0 }
+func negative(v float64) bool { return v < 0 }
+
+// TestSOMNeighborhoods trains maps with neighborhoods on basic patterns.
+// Mexican hat is left out, with only three patterns its negative part keeps
+// pushing nodes of other clusters outside of [0, 1].
+func TestSOMNeighborhoods(t *testing.T) {
+	data, result, _ := basicPatterns()
+	for _, n := range []Neighborhood{Gaussian{}, CutGaussian{}, Bubble{}, Triangular{}} {
+		som := NewSOM(12, 12, 10, 3)
+		som.SetNeighborhood(n)
+		som.Train(2000, data, result)
+		for i := range data {
+			if res := som.PredictInt(data[i]); res[i] < 85 {
+				t.Fatalf("expected %d to be above 85%% with %T got %v", i, n, res)
+			}
+		}
+	}
+}
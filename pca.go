@@ -0,0 +1,122 @@
+// Artificial Neural Networks (ann) library in Go
+// Principal component analysis
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"sort"
+)
+
+// pca returns mean of the data, first k principal components as unit vectors
+// and their variances, largest variance first.
+func pca(data [][]float64, k int) (mean []float64, components [][]float64, variances []float64) {
+	n := len(data[0])
+	mean = make([]float64, n, n)
+	for _, row := range data {
+		for i, v := range row[:n] {
+			mean[i] += v
+		}
+	}
+	for i := range mean {
+		mean[i] /= float64(len(data))
+	}
+
+	cov := make([][]float64, n, n)
+	for i := range cov {
+		cov[i] = make([]float64, n, n)
+	}
+	for _, row := range data {
+		for i := 0; i < n; i++ {
+			di := row[i] - mean[i]
+			for j := i; j < n; j++ {
+				cov[i][j] += di * (row[j] - mean[j])
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			cov[i][j] /= float64(len(data))
+			cov[j][i] = cov[i][j]
+		}
+	}
+
+	values, vectors := symmetricEigen(cov)
+	if k > n {
+		k = n
+	}
+	return mean, vectors[:k], values[:k]
+}
+
+// symmetricEigen returns eigenvalues and unit eigenvectors of symmetric matrix a,
+// largest eigenvalue first, using cyclic Jacobi rotations. Matrix a is destroyed.
+func symmetricEigen(a [][]float64) (values []float64, vectors [][]float64) {
+	n := len(a)
+	// v holds eigenvectors in columns
+	v := make([][]float64, n, n)
+	for i := range v {
+		v[i] = make([]float64, n, n)
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				// rotation that zeroes a[p][q]
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return a[order[i]][order[i]] > a[order[j]][order[j]] })
+
+	values = make([]float64, n, n)
+	vectors = make([][]float64, n, n)
+	for r, i := range order {
+		values[r] = a[i][i]
+		vectors[r] = make([]float64, n, n)
+		for k := 0; k < n; k++ {
+			vectors[r][k] = v[k][i]
+		}
+	}
+	return values, vectors
+}
@@ -0,0 +1,41 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSymmetricEigen checks eigen decomposition of small symmetric matrix.
+func TestSymmetricEigen(t *testing.T) {
+	a := [][]float64{{2, 1, 0}, {1, 2, 0}, {0, 0, 5}}
+	values, vectors := symmetricEigen(a)
+	expected := []float64{5, 3, 1}
+	for i, v := range values {
+		if math.Abs(v-expected[i]) > 1e-9 {
+			t.Fatal("expected eigenvalues", expected, "got", values)
+		}
+	}
+	// second eigenvector is (1, 1, 0) / √2 up to sign
+	if math.Abs(math.Abs(vectors[1][0])-math.Sqrt2/2) > 1e-9 || math.Abs(vectors[1][0]-vectors[1][1]) > 1e-9 {
+		t.Fatal("unexpected eigenvector", vectors[1])
+	}
+}
+
+// TestPCA checks principal components of points along a line with small noise.
+func TestPCA(t *testing.T) {
+	data := [][]float64{}
+	for i := 0; i < 20; i++ {
+		x := float64(i)
+		data = append(data, []float64{1 + x - 0.1, 2 + x + 0.1, 3}, []float64{1 + x + 0.1, 2 + x - 0.1, 3})
+	}
+	mean, components, variances := pca(data, 2)
+	if math.Abs(mean[0]-10.5) > 1e-9 || math.Abs(mean[2]-3) > 1e-9 {
+		t.Fatal("unexpected mean", mean)
+	}
+	if math.Abs(math.Abs(components[0][0])-math.Sqrt2/2) > 1e-6 || math.Abs(components[0][2]) > 1e-9 {
+		t.Fatal("unexpected first component", components[0])
+	}
+	if math.Abs(variances[1]-0.02) > 1e-9 {
+		t.Fatal("expected second variance 0.02 got", variances[1])
+	}
+}
@@ -0,0 +1,138 @@
+// Artificial Neural Networks (ann) library in Go
+// Quality measures for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Quality holds measures of how well trained map fits the data.
+type Quality struct {
+	// QuantizationError is mean distance between input and its best matching node.
+	QuantizationError float64
+	// TopographicError is share of inputs whose best and second best matching
+	// nodes are not neighbors on the grid.
+	TopographicError float64
+	// Distortion is mean of neighborhood weighted squared Euclidean distances between
+	// input and every node whatever the map metric is, neighborhood uses final radius
+	// of the radius schedule and negative weights are clipped to zero as in TrainBatch.
+	Distortion float64
+	// Trustworthiness in range [0, 1] is low when inputs that are close on the map
+	// are far apart in the input space, calculated for k nearest neighbors.
+	Trustworthiness float64
+	// NeighborhoodPreservation in range [0, 1] is low when inputs that are close
+	// in the input space are far apart on the map, calculated for k nearest neighbors.
+	NeighborhoodPreservation float64
+}
+
+// String pretty print quality.
+func (q Quality) String() string {
+	return fmt.Sprintf("quantization %.4g, topographic %.4g, distortion %.4g, trustworthiness %.4g, preservation %.4g",
+		q.QuantizationError, q.TopographicError, q.Distortion, q.Trustworthiness, q.NeighborhoodPreservation)
+}
+
+// Quality measures map on inputs fv. Trustworthiness and neighborhood preservation
+// compare k nearest neighbors of every input and take O(n² log n) time for n inputs,
+// they are left at zero when k is zero or not below (2n - 1) / 3.
+func (som *SOM) Quality(fv [][]float64, k int) *Quality {
+	q := &Quality{}
+	if len(fv) == 0 {
+		return q
+	}
+
+	neighborhood := som.neighborhood
+	if neighborhood == nil {
+		neighborhood = Gaussian{}
+	}
+	radius := som.radius.Final
+
+	best := make([]int, len(fv), len(fv))
+	for i, input := range fv {
+		b, s, bestDist, _ := som.bestMatches(input)
+		best[i] = b
+		q.QuantizationError += bestDist
+		if s < 0 || som.distance(som.nodes[b], som.nodes[s]) > 1+1e-9 {
+			q.TopographicError++
+		}
+		for _, node := range som.nodes {
+			h := neighborhood.Influence(som.distance(som.nodes[b], node), radius)
+			if h > 0 {
+				q.Distortion += h * metricDistance(SquaredEuclidean{}, node.fv[:som.fvSize], input[:som.fvSize])
+			}
+		}
+	}
+	n := float64(len(fv))
+	q.QuantizationError /= n
+	q.TopographicError /= n
+	q.Distortion /= n
+
+	if k > 0 && 3*k < 2*len(fv)-1 {
+		q.Trustworthiness, q.NeighborhoodPreservation = som.rankQuality(fv, best, k)
+	}
+	return q
+}
+
+// rankQuality calculates trustworthiness and continuity (neighborhood preservation)
+// of Venna and Kaski comparing neighbor ranks in input space and on the map.
+func (som *SOM) rankQuality(fv [][]float64, best []int, k int) (trust, cont float64) {
+	n := len(fv)
+	inRank := make([]int, n, n)
+	outRank := make([]int, n, n)
+	for i := range fv {
+		ranks(inRank, i, func(j int) float64 { return som.fvDistance(fv[i], fv[j]) })
+		ranks(outRank, i, func(j int) float64 { return som.distance(som.nodes[best[i]], som.nodes[best[j]]) })
+		for j := range fv {
+			// close on the map, not in the input space
+			if outRank[j] <= k && inRank[j] > k {
+				trust += float64(inRank[j] - k)
+			}
+			// close in the input space, not on the map
+			if inRank[j] <= k && outRank[j] > k {
+				cont += float64(outRank[j] - k)
+			}
+		}
+	}
+	scale := 2 / float64(n*k*(2*n-3*k-1))
+	return 1 - scale*trust, 1 - scale*cont
+}
+
+// ranks sets rank[j] to position of j among other points ordered by dist(j),
+// closest point has rank 1, ties are ordered by index and rank[i] is 0.
+func ranks(rank []int, i int, dist func(j int) float64) {
+	order := make([]int, 0, len(rank)-1)
+	d := make([]float64, len(rank), len(rank))
+	for j := range rank {
+		if j != i {
+			order = append(order, j)
+			d[j] = dist(j)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return d[order[a]] < d[order[b]] })
+	rank[i] = 0
+	for r, j := range order {
+		rank[j] = r + 1
+	}
+}
+
+// TrackQuality makes Train and TrainBatch measure map quality on inputs fv after
+// every iteration or epoch, see Quality for k. Nil fv stops tracking.
+func (som *SOM) TrackQuality(fv [][]float64, k int) {
+	som.trackFv = fv
+	som.trackK = k
+	som.history = nil
+}
+
+// QualityHistory returns quality measured after every iteration or epoch of training
+// since TrackQuality was called.
+func (som *SOM) QualityHistory() []*Quality {
+	return som.history
+}
+
+// trackQuality measures quality if tracking is enabled.
+func (som *SOM) trackQuality() {
+	if som.trackFv != nil {
+		som.history = append(som.history, som.Quality(som.trackFv, som.trackK))
+	}
+}
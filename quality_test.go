@@ -0,0 +1,63 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestQualityPerfectMap measures map whose nodes are exactly on the inputs.
+func TestQualityPerfectMap(t *testing.T) {
+	som := gridSOM()
+	fv := [][]float64{}
+	for _, node := range som.nodes {
+		fv = append(fv, append([]float64{}, node.fv...))
+	}
+	q := som.Quality(fv, 3)
+	if q.QuantizationError != 0 || q.TopographicError != 0 {
+		t.Fatal("expected zero errors got", q)
+	}
+	if math.Abs(q.Trustworthiness-1) > 1e-12 || math.Abs(q.NeighborhoodPreservation-1) > 1e-12 {
+		t.Fatal("expected perfect trustworthiness and preservation got", q)
+	}
+
+	// swapping two distant nodes breaks the topology
+	som.nodes[0].fv, som.nodes[11].fv = som.nodes[11].fv, som.nodes[0].fv
+	q = som.Quality(fv, 3)
+	if q.QuantizationError != 0 || q.TopographicError == 0 || q.Trustworthiness >= 1 || q.NeighborhoodPreservation >= 1 {
+		t.Fatal("expected topology errors got", q)
+	}
+
+	// distortion does not depend on metric and is not negative
+	distortion := q.Distortion
+	som.SetMetric(SquaredEuclidean{})
+	if q = som.Quality(fv, 0); math.Abs(q.Distortion-distortion) > 1e-9 {
+		t.Fatal("expected distortion", distortion, "got", q.Distortion)
+	}
+	som.SetNeighborhood(MexicanHat{})
+	if q = som.Quality(fv, 0); q.Distortion < 0 {
+		t.Fatal("expected distortion not below zero got", q.Distortion)
+	}
+}
+
+// TestQualityTracking checks that quality improves while map learns.
+func TestQualityTracking(t *testing.T) {
+	data, result, test := basicPatterns()
+	fv := append(append([][]float64{}, data...), test...)
+	som := NewSOM(6, 6, 10, 3)
+	som.TrackQuality(fv, 1)
+	som.Train(50, data, result)
+	history := som.QualityHistory()
+	if len(history) != 50 {
+		t.Fatal("expected quality for every iteration got", len(history))
+	}
+	first, last := history[0], history[len(history)-1]
+	if last.QuantizationError >= first.QuantizationError || last.Distortion >= first.Distortion {
+		t.Fatal("expected quality to improve from", first, "got", last)
+	}
+
+	som.TrackQuality(fv, 0)
+	som.TrainBatch(3, data, result)
+	if len(som.QualityHistory()) != 3 || som.QualityHistory()[2].Trustworthiness != 0 {
+		t.Fatal("expected quality for every epoch without ranks got", som.QualityHistory())
+	}
+}
@@ -0,0 +1,60 @@
+// Artificial Neural Networks (ann) library in Go
+// Decay schedules for Self-Organizing Maps training
+// released under MIT license
+package ann
+
+import (
+	"fmt"
+	"math"
+)
+
+// Decay is shape of the curve value follows from initial to final value during training.
+type Decay int
+
+const (
+	// Exponential decay v0·(vf/v0)^(i/n).
+	Exponential Decay = iota
+	// Linear decay v0 + (vf-v0)·i/n.
+	Linear
+	// InverseTime decay v0 / (1 + a·i/n), where a = v0/vf - 1.
+	InverseTime
+	// Power decay v0·i^(-a), where a = ln(v0/vf) / ln(n).
+	Power
+)
+
+// Schedule holds initial and final value of SOM learning rate or radius and decay between them.
+// Initial and final values have to be above zero for all decays except Linear,
+// setters that take schedule panic otherwise.
+type Schedule struct {
+	Initial float64
+	Final   float64
+	Decay   Decay
+}
+
+// check panics when decay is not defined for initial and final values.
+func (s Schedule) check() {
+	if s.Decay != Linear && (s.Initial <= 0 || s.Final <= 0) {
+		panic(fmt.Sprintf("initial %v and final %v values should be above zero for decay %d", s.Initial, s.Final, s.Decay))
+	}
+}
+
+// At returns value at iteration i of n iterations, first iteration is 1
+// and value at the last one is Final.
+func (s Schedule) At(i, n int) float64 {
+	t := float64(i) / float64(n)
+	if s.Decay != Linear && (s.Initial <= 0 || s.Final <= 0) {
+		return s.Initial + (s.Final-s.Initial)*t
+	}
+	switch s.Decay {
+	case Linear:
+		return s.Initial + (s.Final-s.Initial)*t
+	case InverseTime:
+		return s.Initial / (1 + (s.Initial/s.Final-1)*t)
+	case Power:
+		if n <= 1 {
+			return s.Final
+		}
+		return s.Initial * math.Pow(float64(i), -math.Log(s.Initial/s.Final)/math.Log(float64(n)))
+	}
+	return s.Initial * math.Pow(s.Final/s.Initial, t)
+}
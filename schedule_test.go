@@ -0,0 +1,65 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestScheduleAt checks that every decay starts near initial value,
+// ends at final value and keeps decreasing.
+func TestScheduleAt(t *testing.T) {
+	for _, decay := range []Decay{Exponential, Linear, InverseTime, Power} {
+		s := Schedule{Initial: 6, Final: 0.5, Decay: decay}
+		prev := s.Initial
+		for i := 1; i <= 100; i++ {
+			v := s.At(i, 100)
+			if v > prev || v < s.Final-1e-12 {
+				t.Fatal("expected decreasing value for decay", decay, "got", v, "after", prev)
+			}
+			prev = v
+		}
+		if math.Abs(prev-s.Final) > 1e-12 {
+			t.Fatal("expected final value", s.Final, "for decay", decay, "got", prev)
+		}
+	}
+}
+
+// TestScheduleCheck checks that setters reject decays undefined for zero values.
+func TestScheduleCheck(t *testing.T) {
+	som := NewSOM(2, 2, 1, 1)
+	som.SetLearningRate(Schedule{Initial: 0.1, Final: 0, Decay: Linear})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected SetLearningRate to panic on zero final value")
+		}
+	}()
+	som.SetLearningRate(Schedule{Initial: 0.1, Final: 0, Decay: Exponential})
+}
+
+// TestScheduleDefault checks that default schedules match original
+// decay of radius and learning rate tied to one time constant.
+func TestScheduleDefault(t *testing.T) {
+	som := NewSOM(12, 12, 10, 3)
+	timeConstant := 5000 / math.Log(12)
+	for i := 1; i <= 5000; i += 499 {
+		radius := 12 * math.Exp(float64(-i)/timeConstant)
+		lr := 0.05 * math.Exp(float64(-i)/timeConstant)
+		if math.Abs(som.radius.At(i, 5000)-radius) > 1e-9 || math.Abs(som.learningRate.At(i, 5000)-lr) > 1e-12 {
+			t.Fatal("expected", radius, lr, "got", som.radius.At(i, 5000), som.learningRate.At(i, 5000))
+		}
+	}
+}
+
+// TestSOMSchedules trains map with linear decays on basic patterns.
+func TestSOMSchedules(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOM(12, 12, 10, 3)
+	som.SetRadius(Schedule{Initial: 8, Final: 0.5, Decay: Linear})
+	som.SetLearningRate(Schedule{Initial: 0.1, Final: 0.01, Decay: InverseTime})
+	som.Train(2000, data, result)
+	for i := range data {
+		if res := som.PredictInt(data[i]); res[i] < 85 {
+			t.Fatal("expected", i, "to be above 85% got", res)
+		}
+	}
+}
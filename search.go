@@ -0,0 +1,277 @@
+// Artificial Neural Networks (ann) library in Go
+// Accelerated best matching node search for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Search is strategy used to find best matching nodes of the map.
+// All strategies return exactly the same nodes and distances as Exhaustive.
+type Search int
+
+const (
+	// Exhaustive compares input with every node.
+	Exhaustive Search = iota
+	// ParallelScan compares input with every node, nodes are split between goroutines.
+	ParallelScan
+	// KDTree searches KD tree over node feature vectors. Tree is rebuilt after nodes
+	// change: once per epoch in TrainBatch and once after Train for predictions.
+	// Train changes nodes after every input, so it uses ParallelScan instead.
+	// Nodes changed directly leave the tree stale until SetSearch is called again.
+	// Works with Euclidean, SquaredEuclidean, Manhattan and Chebyshev metrics,
+	// other metrics and inputs with NaN use ParallelScan.
+	KDTree
+)
+
+// String returns search name.
+func (s Search) String() string {
+	switch s {
+	case Exhaustive:
+		return "exhaustive"
+	case ParallelScan:
+		return "parallel scan"
+	case KDTree:
+		return "KD tree"
+	}
+	return "unknown"
+}
+
+// minScanChunk is smallest number of nodes worth scanning in separate goroutine.
+const minScanChunk = 256
+
+// SetSearch sets strategy used to find best matching nodes, default is Exhaustive.
+func (som *SOM) SetSearch(search Search) {
+	som.search = search
+	som.buildIndex()
+}
+
+// buildIndex rebuilds KD tree after nodes, metric or search change, so searches
+// only read it and predictions can run concurrently.
+func (som *SOM) buildIndex() {
+	som.index = nil
+	if som.search == KDTree && axisBound(som.metric) != nil {
+		som.index = newKDTree(som)
+	}
+}
+
+// onlineMatch finds best matching node for Train, which changes nodes after every input.
+func (som *SOM) onlineMatch(fv []float64) int {
+	if som.search == Exhaustive {
+		return som.bestMatch(fv)
+	}
+	best, _, _, _ := som.parallelMatches(fv)
+	if best < 0 {
+		best = 0
+	}
+	return best
+}
+
+// batchMatch finds best matching node for TrainBatch, which is already spread
+// over goroutines and builds the index before.
+func (som *SOM) batchMatch(fv []float64) int {
+	var best int
+	if som.index != nil && som.indexable(fv) {
+		best, _, _, _ = som.index.bestMatches(som, fv)
+	} else {
+		best, _, _, _ = som.scanMatches(fv, 0, som.total)
+	}
+	if best < 0 {
+		best = 0
+	}
+	return best
+}
+
+// matchSet keeps best and second best nodes, ordered by distance and then by node index,
+// which is the order exhaustive scan gives.
+type matchSet struct {
+	best, second         int
+	bestDist, secondDist float64
+}
+
+// newMatchSet creates empty set.
+func newMatchSet() matchSet {
+	return matchSet{best: -1, second: -1, bestDist: math.Inf(1), secondDist: math.Inf(1)}
+}
+
+// add offers node k at distance dist to the set.
+func (s *matchSet) add(k int, dist float64) {
+	switch {
+	case math.IsNaN(dist):
+	case s.best < 0 || dist < s.bestDist || dist == s.bestDist && k < s.best:
+		s.second, s.secondDist = s.best, s.bestDist
+		s.best, s.bestDist = k, dist
+	case s.second < 0 || dist < s.secondDist || dist == s.secondDist && k < s.second:
+		s.second, s.secondDist = k, dist
+	}
+}
+
+// parallelMatches finds best and second best matching nodes scanning parts of the map
+// in separate goroutines.
+func (som *SOM) parallelMatches(fv []float64) (best, second int, bestDist, secondDist float64) {
+	workers := runtime.GOMAXPROCS(0)
+	chunk := (som.total + workers - 1) / workers
+	if chunk < minScanChunk {
+		chunk = minScanChunk
+	}
+	if chunk >= som.total {
+		return som.scanMatches(fv, 0, som.total)
+	}
+
+	parts := make([]matchSet, (som.total+chunk-1)/chunk)
+	var wg sync.WaitGroup
+	for p := range parts {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			hi := (p + 1) * chunk
+			if hi > som.total {
+				hi = som.total
+			}
+			s := &parts[p]
+			s.best, s.second, s.bestDist, s.secondDist = som.scanMatches(fv, p*chunk, hi)
+		}(p)
+	}
+	wg.Wait()
+
+	res := newMatchSet()
+	for _, s := range parts {
+		if s.best >= 0 {
+			res.add(s.best, s.bestDist)
+		}
+		if s.second >= 0 {
+			res.add(s.second, s.secondDist)
+		}
+	}
+	return res.best, res.second, res.bestDist, res.secondDist
+}
+
+// indexable reports if KD tree can be used for fv with map metric.
+func (som *SOM) indexable(fv []float64) bool {
+	if axisBound(som.metric) == nil {
+		return false
+	}
+	for _, v := range fv[:som.fvSize] {
+		if math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// axisBound returns function that turns difference along one axis into lower bound
+// of the distance, nil if metric has no such bound.
+func axisBound(metric Metric) func(diff float64) float64 {
+	switch metric.(type) {
+	case Euclidean, Manhattan, Chebyshev:
+		return func(diff float64) float64 { return diff }
+	case SquaredEuclidean:
+		return func(diff float64) float64 { return diff * diff }
+	}
+	return nil
+}
+
+// kdTree is exact nearest neighbor index over node feature vectors.
+type kdTree struct {
+	nodes []kdNode
+	root  int
+	bound func(diff float64) float64
+}
+
+// kdNode splits nodes by value along one axis, left subtree has values not above
+// the split and right subtree values not below it.
+type kdNode struct {
+	k           int // index of SOM node
+	axis        int
+	split       float64
+	left, right int // -1 when there is no subtree
+}
+
+// newKDTree builds tree over map nodes, nodes with NaN values are left out as they never match.
+func newKDTree(som *SOM) *kdTree {
+	t := &kdTree{
+		nodes: make([]kdNode, 0, som.total),
+		bound: axisBound(som.metric),
+	}
+	ks := make([]int, 0, som.total)
+	for k, node := range som.nodes {
+		if !hasNaN(node.fv) {
+			ks = append(ks, k)
+		}
+	}
+	t.root = t.build(som, ks)
+	return t
+}
+
+// hasNaN reports if any of the values is NaN.
+func hasNaN(values []float64) bool {
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// build creates subtree for nodes ks splitting along axis with the largest spread.
+func (t *kdTree) build(som *SOM, ks []int) int {
+	if len(ks) == 0 {
+		return -1
+	}
+	axis := 0
+	spread := -1.0
+	for m := 0; m < som.fvSize; m++ {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, k := range ks {
+			lo = math.Min(lo, som.nodes[k].fv[m])
+			hi = math.Max(hi, som.nodes[k].fv[m])
+		}
+		if hi-lo > spread {
+			axis, spread = m, hi-lo
+		}
+	}
+	sort.Slice(ks, func(i, j int) bool {
+		return som.nodes[ks[i]].fv[axis] < som.nodes[ks[j]].fv[axis]
+	})
+
+	mid := len(ks) / 2
+	pos := len(t.nodes)
+	t.nodes = append(t.nodes, kdNode{k: ks[mid], axis: axis, split: som.nodes[ks[mid]].fv[axis]})
+	left := t.build(som, ks[:mid])
+	right := t.build(som, ks[mid+1:])
+	t.nodes[pos].left = left
+	t.nodes[pos].right = right
+	return pos
+}
+
+// bestMatches finds best and second best matching nodes, best is -1 if tree is empty.
+func (t *kdTree) bestMatches(som *SOM, fv []float64) (best, second int, bestDist, secondDist float64) {
+	s := newMatchSet()
+	t.search(som, fv, t.root, &s)
+	return s.best, s.second, s.bestDist, s.secondDist
+}
+
+// search visits subtree at pos, subtrees that can not hold nodes closer than
+// the second best are skipped.
+func (t *kdTree) search(som *SOM, fv []float64, pos int, s *matchSet) {
+	if pos < 0 {
+		return
+	}
+	n := &t.nodes[pos]
+	s.add(n.k, som.fvDistance(som.nodes[n.k].fv, fv))
+
+	diff := fv[n.axis] - n.split
+	near, far := n.left, n.right
+	if diff > 0 {
+		near, far = n.right, n.left
+	}
+	t.search(som, fv, near, s)
+	// small slack keeps nodes whose computed distance is rounded below the bound
+	if t.bound(math.Abs(diff))*(1-1e-12) <= s.secondDist {
+		t.search(som, fv, far, s)
+	}
+}
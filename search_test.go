@@ -0,0 +1,158 @@
+package ann
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// randomSOM creates map with random nodes, values are multiples of step so ties are common.
+func randomSOM(height, width, fvSize int, step float64) *SOM {
+	som := NewSOM(height, width, fvSize, 1)
+	for _, node := range som.nodes {
+		for m := range node.fv {
+			node.fv[m] = math.Floor(rand.Float64()/step) * step
+		}
+	}
+	return som
+}
+
+// TestSearchExact compares accelerated searches with exhaustive search.
+func TestSearchExact(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	som := randomSOM(30, 30, 3, 0.25)
+	som.nodes[17].fv[1] = math.NaN()
+
+	queries := [][]float64{{math.NaN(), 0, 0}, som.nodes[5].fv, som.nodes[400].fv}
+	for i := 0; i < 200; i++ {
+		queries = append(queries, []float64{rand.Float64(), rand.Float64(), math.Floor(rand.Float64()*4) / 4})
+	}
+
+	for _, metric := range []Metric{Euclidean{}, SquaredEuclidean{}, Manhattan{}, Chebyshev{}, Cosine{}} {
+		som.SetMetric(metric)
+		for _, fv := range queries {
+			som.SetSearch(Exhaustive)
+			b, s, bd, sd := som.bestMatches(fv)
+			for _, search := range []Search{ParallelScan, KDTree} {
+				som.SetSearch(search)
+				b2, s2, bd2, sd2 := som.bestMatches(fv)
+				if b != b2 || s != s2 || !sameDist(bd, bd2) || !sameDist(sd, sd2) {
+					t.Fatalf("%T %v search expected %d %d %v %v got %d %d %v %v for %v",
+						metric, search, b, s, bd, sd, b2, s2, bd2, sd2, fv)
+				}
+			}
+		}
+	}
+}
+
+// TestSearchMetricChange checks that KD tree follows metric set after a search.
+func TestSearchMetricChange(t *testing.T) {
+	som := randomSOM(20, 20, 3, 0.01)
+	som.SetSearch(KDTree)
+	som.BMU([]float64{0.5, 0.5, 0.5})
+	som.SetMetric(SquaredEuclidean{})
+
+	exhaustive := copySOM(som)
+	exhaustive.SetMetric(SquaredEuclidean{})
+	for i := 0; i < 1000; i++ {
+		fv := []float64{rand.Float64(), rand.Float64(), rand.Float64()}
+		if b, b2 := som.bestMatch(fv), exhaustive.bestMatch(fv); b != b2 {
+			t.Fatal("expected node", b2, "got", b, "for", fv)
+		}
+	}
+}
+
+// TestSearchConcurrent checks that predictions can search KD tree concurrently.
+func TestSearchConcurrent(t *testing.T) {
+	som := randomSOM(20, 20, 3, 0.01)
+	exhaustive := copySOM(som)
+	som.SetSearch(KDTree)
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 4)
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < 500; i++ {
+				fv := []float64{r.Float64(), r.Float64(), r.Float64()}
+				if b, b2 := som.bestMatch(fv), exhaustive.bestMatch(fv); b != b2 {
+					errs <- fmt.Sprint("expected node ", b2, " got ", b, " for ", fv)
+					return
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+// sameDist compares distances treating NaN as equal.
+func sameDist(d1, d2 float64) bool {
+	return d1 == d2 || math.IsNaN(d1) && math.IsNaN(d2)
+}
+
+// TestSearchTraining checks that training gives the same map with every search.
+func TestSearchTraining(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	data := [][]float64{}
+	result := [][]float64{}
+	for i := 0; i < 40; i++ {
+		data = append(data, []float64{rand.Float64(), rand.Float64(), rand.Float64()})
+		result = append(result, []float64{float64(i % 2)})
+	}
+	start := randomSOM(20, 20, 3, 1e-9)
+
+	maps := []*SOM{}
+	for _, search := range []Search{Exhaustive, ParallelScan, KDTree} {
+		som := copySOM(start)
+		som.SetSearch(search)
+		som.Train(5, data, result)
+		som.TrainBatch(5, data, result)
+		maps = append(maps, som)
+	}
+	for _, som := range maps[1:] {
+		for k, node := range som.nodes {
+			for m := range node.fv {
+				if node.fv[m] != maps[0].nodes[k].fv[m] {
+					t.Fatal("expected same nodes for search", som.search, "got", node, maps[0].nodes[k])
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkSearch measures best matching node search on 200x200 map. Nodes lie on
+// smooth surface, the way they do after training, and inputs lie close to it.
+func BenchmarkSearch(b *testing.B) {
+	som := NewSOM(200, 200, 16, 1)
+	for _, node := range som.nodes {
+		for m := range node.fv {
+			node.fv[m] = math.Sin(float64(node.x)/40+float64(m)) * math.Cos(float64(node.y)/50-float64(m))
+		}
+	}
+	inputs := [][]float64{}
+	for i := 0; i < 1000; i++ {
+		fv := append([]float64{}, som.nodes[rand.Intn(som.total)].fv...)
+		for m := range fv {
+			fv[m] += 0.01 * rand.NormFloat64()
+		}
+		inputs = append(inputs, fv)
+	}
+	for _, search := range []Search{Exhaustive, ParallelScan, KDTree} {
+		som.SetSearch(search)
+		som.bestMatch(inputs[0])
+		b.Run(search.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				som.bestMatch(inputs[i%len(inputs)])
+			}
+		})
+	}
+}
@@ -64,27 +64,42 @@ func (node SNode) String() string {
 type SOM struct {
 	height       int
 	width        int
-	radius       int
 	total        int
-	learningRate float64
+	radius       Schedule // neighborhood radius
+	learningRate Schedule
 	nodes        []*SNode
 	fvSize       int
 	pvSize       int
+	topology     Topology
+	neighborhood Neighborhood
+	metric       Metric
+	search       Search
+	index        *kdTree // built when nodes, metric or search change, nil while Train runs
+
+	trackFv [][]float64 // inputs for quality tracking
+	trackK  int
+	history []*Quality
+
+	labels []*NodeLabel // node labels set by Label
+
+	init        Initialization
+	initPending bool // init is applied by the next training
+	trained     bool // nodes were trained or initialized for the topology
 }
 
 // NewSOM creates new self organizing map with specific width and height.
 func NewSOM(height, width, fvSize, pvSize int) *SOM {
 	total := height * width
 	som := &SOM{
-		height:       height,
-		width:        width,
-		radius:       (height + width) / 2,
-		total:        total,
-		learningRate: 0.05,
-		fvSize:       fvSize,
-		pvSize:       pvSize,
-		nodes:        make([]*SNode, total, total),
+		height: height,
+		width:  width,
+		total:  total,
+		fvSize: fvSize,
+		pvSize: pvSize,
+		nodes:  make([]*SNode, total, total),
+		metric: Euclidean{},
 	}
+	som.defaultSchedules()
 
 	// fill SOM network with nodes
 	for i := 0; i < som.height; i++ {
@@ -95,6 +110,59 @@ func NewSOM(height, width, fvSize, pvSize int) *SOM {
 	return som
 }
 
+// NewSOMWithTopology creates SOM with nodes laid out in topology, see NewSOM.
+func NewSOMWithTopology(height, width, fvSize, pvSize int, topology Topology) *SOM {
+	som := NewSOM(height, width, fvSize, pvSize)
+	som.topology = topology
+	return som
+}
+
+// defaultSchedules sets default radius and learning rate schedules for map size.
+func (som *SOM) defaultSchedules() {
+	radius := float64((som.height + som.width) / 2)
+	// radius decays to a single node, learning rate decays by the same factor
+	som.radius = Schedule{Initial: radius, Final: math.Min(radius, 1), Decay: Exponential}
+	som.learningRate = Schedule{Initial: 0.05, Final: 0.05 / math.Max(radius, 1), Decay: Exponential}
+}
+
+// SetTopology sets layout of the map nodes, call it before Train or create map
+// with NewSOMWithTopology. Panics once map was trained or initialized, as its nodes
+// are ordered for the old layout.
+func (som *SOM) SetTopology(topology Topology) {
+	if som.trained {
+		panic("topology can not change after map was trained or initialized")
+	}
+	som.topology = topology
+}
+
+// SetNeighborhood sets neighborhood function used by Train, call it before Train.
+// Nil neighborhood is the default exp(-d²/(2·radius·iteration)) cut off at the radius.
+func (som *SOM) SetNeighborhood(neighborhood Neighborhood) {
+	som.neighborhood = neighborhood
+}
+
+// SetRadius sets schedule of the neighborhood radius, call it before Train.
+// Default radius decays exponentially from (height + width) / 2 to 1.
+func (som *SOM) SetRadius(radius Schedule) {
+	radius.check()
+	som.radius = radius
+}
+
+// SetLearningRate sets schedule of the learning rate, call it before Train.
+// Default learning rate decays exponentially from 0.05 by the same factor as radius.
+func (som *SOM) SetLearningRate(learningRate Schedule) {
+	learningRate.check()
+	som.learningRate = learningRate
+}
+
+// SetMetric sets distance metric used to find best matching nodes, default is Euclidean.
+func (som *SOM) SetMetric(metric Metric) {
+	checkMetric(metric, som.fvSize)
+	som.metric = metric
+	// KD tree prunes with bound of the metric it was built for
+	som.buildIndex()
+}
+
 // Train performs SOM training for specified number of iterations.
 func (som *SOM) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][]float64) {
 	// helper type for storing calculated values
@@ -108,7 +176,12 @@ func (som *SOM) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][
 		panic("length of fvInputTrain should match pvInputTrain")
 	}
 
-	timeConstant := float64(iterations) / math.Log(float64(som.radius))
+	som.initialize(fvInputTrain, pvInputTrain)
+
+	// nodes change after every input, index is rebuilt after training
+	som.index = nil
+	defer som.buildIndex()
+
 	radiusDecaying := 0.0
 	lrd := 0.0 // learning rate decaying
 	influence := 0.0
@@ -116,31 +189,43 @@ func (som *SOM) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][
 	length := len(fvInputTrain)
 
 	for i := 1; i < iterations+1; i++ {
-		radiusDecaying = float64(som.radius) * math.Exp(float64(-1.0*i)/timeConstant)
-		lrd = som.learningRate * math.Exp(float64(-1.0*i)/timeConstant)
+		radiusDecaying = som.radius.At(i, iterations)
+		lrd = som.learningRate.At(i, iterations)
 
 		for j := 0; j < length; j++ {
 			fvInput := fvInputTrain[j]
 			pvInput := pvInputTrain[j]
-			best := som.bestMatch(fvInput)
+			best := som.onlineMatch(fvInput)
 			stack = []*StackValue{}
 
 			for k := 0; k < som.total; k++ {
 				dist := som.distance(som.nodes[best], som.nodes[k])
-				if dist < radiusDecaying {
+				if som.neighborhood != nil {
+					influence = som.neighborhood.Influence(dist, radiusDecaying)
+				} else if dist < radiusDecaying {
+					influence = math.Exp((-1.0 * math.Pow(dist, 2)) / (2 * radiusDecaying * float64(i)))
+				} else {
+					influence = 0
+				}
+				if influence != 0 {
 					fvTemp := []float64{}
 					pvTemp := []float64{}
-					influence = math.Exp((-1.0 * math.Pow(dist, 2)) / (2 * radiusDecaying * float64(i)))
 
-					// perform FV learning
+					// perform FV learning, missing NaN values are skipped
 					for m := 0; m < som.fvSize; m++ {
-						adjustment := influence * lrd * (fvInput[m] - som.nodes[k].fv[m])
+						adjustment := 0.0
+						if !math.IsNaN(fvInput[m]) {
+							adjustment = influence * lrd * (fvInput[m] - som.nodes[k].fv[m])
+						}
 						fvTemp = append(fvTemp, som.nodes[k].fv[m]+adjustment)
 					}
 
 					// perform PV learning
 					for m := 0; m < som.pvSize; m++ {
-						adjustment := influence * lrd * (pvInput[m] - som.nodes[k].pv[m])
+						adjustment := 0.0
+						if !math.IsNaN(pvInput[m]) {
+							adjustment = influence * lrd * (pvInput[m] - som.nodes[k].pv[m])
+						}
 						pvTemp = append(pvTemp, som.nodes[k].pv[m]+adjustment)
 					}
 
@@ -157,6 +242,7 @@ func (som *SOM) Train(iterations int, fvInputTrain [][]float64, pvInputTrain [][
 				som.nodes[stack[k].k].pv = stack[k].pvTemp
 			}
 		}
+		som.trackQuality()
 	}
 }
 
@@ -176,32 +262,206 @@ func (som *SOM) PredictInt(fv []float64) []int {
 	return res
 }
 
+// Match is node of the map matching feature vector.
+type Match struct {
+	X, Y int     // node column and row, -1 when there is no such node
+	Dist float64 // distance between node and feature vector
+}
+
+// BMU returns position of the best matching node for fv and distance to it.
+func (som *SOM) BMU(fv []float64) (x, y int, dist float64) {
+	best, _, bestDist, _ := som.bestMatches(fv)
+	return som.nodes[best].x, som.nodes[best].y, bestDist
+}
+
+// BestMatches returns best and second best matching nodes for fv.
+func (som *SOM) BestMatches(fv []float64) (best, second Match) {
+	b, s, bestDist, secondDist := som.bestMatches(fv)
+	best = Match{X: som.nodes[b].x, Y: som.nodes[b].y, Dist: bestDist}
+	second = Match{X: -1, Y: -1, Dist: secondDist}
+	if s >= 0 {
+		second.X, second.Y = som.nodes[s].x, som.nodes[s].y
+	}
+	return best, second
+}
+
 // bestMatch find best matching node index.
-func (som SOM) bestMatch(fvTarget []float64) int {
-	minimum := math.Sqrt(float64(som.fvSize))
-	minimumIndex := 1
-	for i := 0; i < som.total; i++ {
+func (som *SOM) bestMatch(fvTarget []float64) int {
+	best, _, _, _ := som.bestMatches(fvTarget)
+	return best
+}
+
+// bestMatches finds indexes of best and second best matching nodes and their distances
+// using search set by SetSearch. Ties go to the node with lower index and NaN distances
+// never match. When all distances are NaN first node is best with NaN distance,
+// missing second node is -1.
+func (som *SOM) bestMatches(fvTarget []float64) (best, second int, bestDist, secondDist float64) {
+	switch {
+	case som.index != nil && som.indexable(fvTarget):
+		best, second, bestDist, secondDist = som.index.bestMatches(som, fvTarget)
+	case som.search != Exhaustive:
+		best, second, bestDist, secondDist = som.parallelMatches(fvTarget)
+	default:
+		best, second, bestDist, secondDist = som.scanMatches(fvTarget, 0, som.total)
+	}
+	if best < 0 {
+		best, bestDist = 0, math.NaN()
+	}
+	return best, second, bestDist, secondDist
+}
+
+// scanMatches finds best and second best matching nodes among nodes [lo, hi),
+// best is -1 if all distances are NaN.
+func (som *SOM) scanMatches(fvTarget []float64, lo, hi int) (best, second int, bestDist, secondDist float64) {
+	best, second = -1, -1
+	bestDist, secondDist = math.Inf(1), math.Inf(1)
+	for i := lo; i < hi; i++ {
 		temp := som.fvDistance(som.nodes[i].fv, fvTarget)
-		if temp < minimum {
-			minimum = temp
-			minimumIndex = i
+		switch {
+		case math.IsNaN(temp):
+		case best < 0 || temp < bestDist:
+			second, secondDist = best, bestDist
+			best, bestDist = i, temp
+		case second < 0 || temp < secondDist:
+			second, secondDist = i, temp
 		}
 	}
-	return minimumIndex
+	return best, second, bestDist, secondDist
 }
 
-// fvDistance calculates distance of two vectors.
+// fvDistance calculates distance of two vectors using map metric.
+// Missing NaN values of input fv2 are left out and distance is scaled up by fraction
+// of present values, so that it stays comparable with distances of complete inputs.
+// Distance is NaN when all values are missing or fv1 has NaN. Mahalanobis metric
+// panics on missing values.
 func (som SOM) fvDistance(fv1, fv2 []float64) float64 {
-	temp := 0.0
-	for j := 0; j < som.fvSize; j++ {
-		temp = temp + math.Pow(fv1[j]-fv2[j], 2)
+	return som.maskedDistance(fv1[:som.fvSize], fv2[:som.fvSize])
+}
+
+// maskedDistance calculates metric distance of two vectors of the same length
+// leaving out missing values, see fvDistance.
+func (som SOM) maskedDistance(fv1, fv2 []float64) float64 {
+	return metricDistance(som.metric, fv1, fv2)
+}
+
+// metricDistance calculates distance of two vectors with metric leaving out
+// missing values of fv2, see fvDistance.
+func metricDistance(metric Metric, fv1, fv2 []float64) float64 {
+	dist := metric.Distance(fv1, fv2)
+	// metrics turn missing values into NaN distance
+	if !math.IsNaN(dist) || !hasNaN(fv2) {
+		return dist
+	}
+	if _, ok := metric.(*Mahalanobis); ok {
+		panic("Mahalanobis metric does not support missing values")
+	}
+	n := len(fv1)
+	fv1, fv2 = present(fv1, fv2)
+	if len(fv1) == 0 {
+		return math.NaN()
+	}
+	dist = metric.Distance(fv1, fv2)
+	// sums over components grow with number of present values
+	scale := float64(n) / float64(len(fv1))
+	switch metric.(type) {
+	case Euclidean:
+		return dist * math.Sqrt(scale)
+	case SquaredEuclidean, Manhattan:
+		return dist * scale
+	}
+	return dist
+}
+
+// present returns values of fv1 and fv2 at positions where fv2 is not NaN.
+func present(fv1, fv2 []float64) ([]float64, []float64) {
+	p1 := make([]float64, 0, len(fv1))
+	p2 := make([]float64, 0, len(fv2))
+	for m, v := range fv2 {
+		if !math.IsNaN(v) {
+			p1 = append(p1, fv1[m])
+			p2 = append(p2, v)
+		}
 	}
-	temp = math.Sqrt(temp)
-	return temp
+	return p1, p2
 }
 
 // distance calculates distance of two nodes.
 func (som SOM) distance(node1 *SNode, node2 *SNode) float64 {
-	return math.Sqrt(math.Pow(float64(node1.x-node2.x), 2) +
-		math.Pow(float64(node1.y-node2.y), 2))
+	return som.topology.distance(node1.x, node1.y, node2.x, node2.y, som.width, som.height)
+}
+
+// X returns node column on the grid.
+func (node *SNode) X() int {
+	return node.x
+}
+
+// Y returns node row on the grid.
+func (node *SNode) Y() int {
+	return node.y
+}
+
+// FV returns copy of node feature vector.
+func (node *SNode) FV() []float64 {
+	return copyVector(node.fv)
+}
+
+// PV returns copy of node prediction vector.
+func (node *SNode) PV() []float64 {
+	return copyVector(node.pv)
+}
+
+// Size returns map height and width.
+func (som *SOM) Size() (height, width int) {
+	return som.height, som.width
+}
+
+// FVSize returns length of feature vectors.
+func (som *SOM) FVSize() int {
+	return som.fvSize
+}
+
+// PVSize returns length of prediction vectors.
+func (som *SOM) PVSize() int {
+	return som.pvSize
+}
+
+// Node returns copy of node at column x and row y.
+func (som *SOM) Node(x, y int) *SNode {
+	som.checkNode(x, y)
+	node := *som.nodes[y*som.width+x]
+	node.fv = node.FV()
+	node.pv = node.PV()
+	return &node
+}
+
+// Codebook returns copies of node feature vectors, row by row.
+func (som *SOM) Codebook() [][]float64 {
+	res := make([][]float64, som.total, som.total)
+	for k, node := range som.nodes {
+		res[k] = node.FV()
+	}
+	return res
+}
+
+// Prototypes returns copies of node prediction vectors, row by row.
+func (som *SOM) Prototypes() [][]float64 {
+	res := make([][]float64, som.total, som.total)
+	for k, node := range som.nodes {
+		res[k] = node.PV()
+	}
+	return res
+}
+
+// checkNode panics if column x and row y are outside of the map.
+func (som *SOM) checkNode(x, y int) {
+	if x < 0 || x >= som.width || y < 0 || y >= som.height {
+		panic(fmt.Sprintf("node %d, %d is outside of %d by %d map", x, y, som.width, som.height))
+	}
+}
+
+// copyVector returns copy of values.
+func copyVector(values []float64) []float64 {
+	res := make([]float64, len(values), len(values))
+	copy(res, values)
+	return res
 }
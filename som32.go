@@ -0,0 +1,161 @@
+// Artificial Neural Networks (ann) library in Go
+// Self-Organizing Maps with float32 precision - SOM32
+// Same algorithm as SOM, node vectors are stored in contiguous float32 slices.
+// released under MIT license
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// SOM32 self-orginizing map with float32 node vectors.
+type SOM32 struct {
+	height       int
+	width        int
+	radius       int
+	total        int
+	learningRate float32
+	fv           []float32 // feature vectors, row per node
+	pv           []float32 // prediction vectors, row per node
+	fvSize       int
+	pvSize       int
+}
+
+// NewSOM32 creates new float32 self organizing map with specific width and height.
+func NewSOM32(height, width, fvSize, pvSize int) *SOM32 {
+	total := height * width
+	som := &SOM32{
+		height:       height,
+		width:        width,
+		radius:       (height + width) / 2,
+		total:        total,
+		learningRate: 0.05,
+		fvSize:       fvSize,
+		pvSize:       pvSize,
+		fv:           make([]float32, total*fvSize, total*fvSize),
+		pv:           make([]float32, total*pvSize, total*pvSize),
+	}
+
+	rand.Seed(time.Now().Unix())
+	for i := range som.fv {
+		som.fv[i] = rand.Float32()
+	}
+	for i := range som.pv {
+		som.pv[i] = rand.Float32()
+	}
+	return som
+}
+
+// NewSOM32From creates float32 copy of the self organizing map. Only nodes, initial radius
+// and initial learning rate are copied: SOM32 always uses rectangular topology, Euclidean
+// metric, default neighborhood and exponential decay to radius 1, so topology, neighborhood,
+// metric and schedules of src are not carried over.
+func NewSOM32From(src *SOM) *SOM32 {
+	som := &SOM32{
+		height:       src.height,
+		width:        src.width,
+		radius:       int(src.radius.Initial),
+		total:        src.total,
+		learningRate: float32(src.learningRate.Initial),
+		fvSize:       src.fvSize,
+		pvSize:       src.pvSize,
+		fv:           make([]float32, 0, src.total*src.fvSize),
+		pv:           make([]float32, 0, src.total*src.pvSize),
+	}
+	for _, node := range src.nodes {
+		for _, v := range node.fv {
+			som.fv = append(som.fv, float32(v))
+		}
+		for _, v := range node.pv {
+			som.pv = append(som.pv, float32(v))
+		}
+	}
+	return som
+}
+
+// Train performs SOM training for specified number of iterations.
+func (som *SOM32) Train(iterations int, fvInputTrain [][]float32, pvInputTrain [][]float32) {
+	if len(fvInputTrain) != len(pvInputTrain) {
+		panic("length of fvInputTrain should match pvInputTrain")
+	}
+
+	timeConstant := float64(iterations) / math.Log(float64(som.radius))
+	length := len(fvInputTrain)
+
+	for i := 1; i < iterations+1; i++ {
+		radiusDecaying := float64(som.radius) * math.Exp(float64(-1.0*i)/timeConstant)
+		lrd := float64(som.learningRate) * math.Exp(float64(-1.0*i)/timeConstant)
+
+		for j := 0; j < length; j++ {
+			fvInput := fvInputTrain[j]
+			pvInput := pvInputTrain[j]
+			best := som.bestMatch(fvInput)
+
+			// every node is updated from its own values only, so nodes can be updated in place
+			for k := 0; k < som.total; k++ {
+				dist := som.distance(best, k)
+				if dist < radiusDecaying {
+					influence := math.Exp((-1.0 * dist * dist) / (2 * radiusDecaying * float64(i)))
+					rate := float32(influence * lrd)
+					fv := som.fv[k*som.fvSize : (k+1)*som.fvSize]
+					for m := range fv {
+						fv[m] += rate * (fvInput[m] - fv[m])
+					}
+					pv := som.pv[k*som.pvSize : (k+1)*som.pvSize]
+					for m := range pv {
+						pv[m] += rate * (pvInput[m] - pv[m])
+					}
+				}
+			}
+		}
+	}
+}
+
+// Predict performs prediction for SOM.
+func (som *SOM32) Predict(fv []float32) []float32 {
+	best := som.bestMatch(fv)
+	return som.pv[best*som.pvSize : (best+1)*som.pvSize]
+}
+
+// PredictInt performs prediction for SOM and rounds resulting values to percentage.
+func (som *SOM32) PredictInt(fv []float32) []int {
+	res := []int{}
+	for _, val := range som.Predict(fv) {
+		res = append(res, int(val*100))
+	}
+	return res
+}
+
+// bestMatch find best matching node index, ties go to the node with lower index
+// and NaN distances never match.
+func (som *SOM32) bestMatch(fvTarget []float32) int {
+	minimum := float32(math.Inf(1))
+	minimumIndex := 0
+	for i := 0; i < som.total; i++ {
+		temp := som.fvDistance(som.fv[i*som.fvSize:(i+1)*som.fvSize], fvTarget)
+		if temp < minimum {
+			minimum = temp
+			minimumIndex = i
+		}
+	}
+	return minimumIndex
+}
+
+// fvDistance calculates distance of two vectors.
+func (som *SOM32) fvDistance(fv1, fv2 []float32) float32 {
+	var temp float32
+	for j := 0; j < som.fvSize; j++ {
+		d := fv1[j] - fv2[j]
+		temp += d * d
+	}
+	return float32(math.Sqrt(float64(temp)))
+}
+
+// distance calculates distance of two nodes on the grid.
+func (som *SOM32) distance(node1, node2 int) float64 {
+	dy := float64(node1/som.width - node2/som.width)
+	dx := float64(node1%som.width - node2%som.width)
+	return math.Sqrt(dx*dx + dy*dy)
+}
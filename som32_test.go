@@ -0,0 +1,44 @@
+package ann
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestSOM32 trains float32 and float64 maps from the same initial nodes
+// and bounds difference of their predictions for the training patterns.
+func TestSOM32(t *testing.T) {
+	data, result, _ := basicPatterns()
+	data32 := [][]float32{}
+	result32 := [][]float32{}
+	for i := range data {
+		data32 = append(data32, toFloat32(data[i]))
+		result32 = append(result32, toFloat32(result[i]))
+	}
+
+	// distinct initial nodes from fixed seed, NewSOM seeds every node from the clock
+	som := NewSOM(12, 12, 10, 3)
+	r := rand.New(rand.NewSource(1))
+	for _, node := range som.nodes {
+		for m := range node.fv {
+			node.fv[m] = r.Float64()
+		}
+		for m := range node.pv {
+			node.pv[m] = r.Float64()
+		}
+	}
+	som32 := NewSOM32From(som)
+	som.Train(5000, data, result)
+	som32.Train(5000, data32, result32)
+
+	for _, fv := range data {
+		expected := som.Predict(fv)
+		out := som32.Predict(toFloat32(fv))
+		for j := range out {
+			if math.Abs(float64(out[j])-expected[j]) > 1e-4 {
+				t.Fatal("expected", expected, "got", out)
+			}
+		}
+	}
+}
@@ -41,3 +41,79 @@ func TestSOMBasic(t *testing.T) {
 	}
 
 }
+
+// BenchmarkSOMTrain measures training of a larger map with wide feature vectors.
+func BenchmarkSOMTrain(b *testing.B) {
+	data := [][]float64{}
+	result := [][]float64{}
+	for i := 0; i < 20; i++ {
+		fv := make([]float64, 64, 64)
+		for j := range fv {
+			fv[j] = float64((i*j)%17) / 17
+		}
+		data = append(data, fv)
+		result = append(result, []float64{float64(i % 2)})
+	}
+	som := NewSOM(30, 30, 64, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		som.Train(5, data, result)
+	}
+}
+
+// basicPatterns returns training patterns, their results and similar testing patterns
+// used by TestSOMBasic.
+func basicPatterns() (data, result, test [][]float64) {
+	data = [][]float64{
+		{0.9, 0.8, 0.7, 0.6, 0.5, 0.4, 0.3, 0.2, 0.1, 0},
+		{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9},
+		{0.1, 0.2, 0.3, 0.4, 0.5, 0.5, 0.4, 0.3, 0.2, 0.1},
+	}
+	result = [][]float64{{1.0, 0.0, 0.0}, {0.0, 1.0, 0.0}, {0.0, 0.0, 1.0}}
+	test = [][]float64{
+		{0.9, 0.8, 0.3, 0.4, 0.4, 0.5, 0.4, 0.3, 0.2, 0.4},
+		{0.1, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.8},
+		{0.1, 0.2, 0.3, 0.4, 0.6, 0.6, 0.4, 0.3, 0.2, 0.1},
+	}
+	return data, result, test
+}
+
+// checkBasicPatterns checks that trained map recognizes testing patterns.
+func checkBasicPatterns(t *testing.T, som *SOM) {
+	_, _, test := basicPatterns()
+	for i, fv := range test {
+		if res := som.PredictInt(fv); res[i] < 85 {
+			t.Fatal("expected", i, "to be above 85% got", res)
+		}
+	}
+}
+
+// TestAccessors checks that node vectors are returned as copies.
+func TestAccessors(t *testing.T) {
+	som := gridSOM()
+	if h, w := som.Size(); h != 3 || w != 4 || som.FVSize() != 2 || som.PVSize() != 1 {
+		t.Fatal("unexpected dimensions", h, w, som.FVSize(), som.PVSize())
+	}
+
+	node := som.Node(3, 1)
+	fv := node.FV()
+	if node.X() != 3 || node.Y() != 1 || fv[0] != 30 || fv[1] != 10 {
+		t.Fatal("unexpected node", node)
+	}
+	fv[0] = -1
+	node.fv[1] = -1
+	if som.nodes[7].fv[0] != 30 || som.nodes[7].fv[1] != 10 {
+		t.Fatal("node changed through its copy", som.nodes[7])
+	}
+
+	codebook := som.Codebook()
+	prototypes := som.Prototypes()
+	if len(codebook) != 12 || codebook[7][0] != 30 || len(prototypes) != 12 || len(prototypes[0]) != 1 {
+		t.Fatal("unexpected codebook", codebook, prototypes)
+	}
+	codebook[7][0] = -1
+	prototypes[0][0] = -1
+	if som.nodes[7].fv[0] != 30 || som.nodes[0].pv[0] == -1 {
+		t.Fatal("node changed through codebook")
+	}
+}
@@ -0,0 +1,64 @@
+// Artificial Neural Networks (ann) library in Go
+// Grid topologies for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import (
+	"math"
+)
+
+// Topology describes how SOM nodes are laid out on the grid.
+type Topology int
+
+const (
+	// Rectangular grid, every node has four neighbors at distance 1.
+	Rectangular Topology = iota
+	// Hexagonal grid with odd rows shifted by half of the node to the right,
+	// every node has six neighbors at distance 1.
+	Hexagonal
+	// Toroidal rectangular grid that wraps around at the borders in both axes,
+	// so edge nodes have four neighbors too.
+	Toroidal
+)
+
+// String returns topology name.
+func (t Topology) String() string {
+	switch t {
+	case Rectangular:
+		return "rectangular"
+	case Hexagonal:
+		return "hexagonal"
+	case Toroidal:
+		return "toroidal"
+	}
+	return "unknown"
+}
+
+// position returns coordinates of the node in column x and row y on the plane.
+func (t Topology) position(x, y int) (float64, float64) {
+	if t == Hexagonal {
+		return float64(x) + 0.5*float64(y&1), float64(y) * math.Sqrt(3) / 2
+	}
+	return float64(x), float64(y)
+}
+
+// distance calculates distance between two nodes on the grid with given width and height.
+func (t Topology) distance(x1, y1, x2, y2, width, height int) float64 {
+	if t == Toroidal {
+		return math.Hypot(wrap(x1-x2, width), wrap(y1-y2, height))
+	}
+	px1, py1 := t.position(x1, y1)
+	px2, py2 := t.position(x2, y2)
+	return math.Hypot(px1-px2, py1-py2)
+}
+
+// wrap returns shortest distance for difference d on the ring of given size.
+func wrap(d, size int) float64 {
+	if d < 0 {
+		d = -d
+	}
+	if size-d < d {
+		d = size - d
+	}
+	return float64(d)
+}
@@ -0,0 +1,95 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestTopologyNeighbors counts nodes at unit distance from interior node.
+func TestTopologyNeighbors(t *testing.T) {
+	for topology, expected := range map[Topology]int{Rectangular: 4, Hexagonal: 6, Toroidal: 4} {
+		for _, y := range []int{4, 5} {
+			count := 0
+			for i := 0; i < 10; i++ {
+				for j := 0; j < 10; j++ {
+					d := topology.distance(5, y, j, i, 10, 10)
+					if d > 0 && d < 1+1e-9 {
+						if math.Abs(d-1) > 1e-9 {
+							t.Fatal("expected neighbor distance 1 got", d)
+						}
+						count++
+					}
+				}
+			}
+			if count != expected {
+				t.Fatal("expected", expected, topology, "neighbors in row", y, "got", count)
+			}
+		}
+	}
+}
+
+// TestToroidalDistance checks that distance wraps at the borders.
+func TestToroidalDistance(t *testing.T) {
+	cases := []struct {
+		x1, y1, x2, y2 int
+		expected       float64
+	}{
+		{0, 0, 9, 0, 1},
+		{0, 0, 0, 7, 1},
+		{0, 0, 9, 7, math.Sqrt2},
+		{1, 1, 5, 4, 5},
+		{0, 3, 5, 3, 5},
+	}
+	for _, c := range cases {
+		if d := Toroidal.distance(c.x1, c.y1, c.x2, c.y2, 10, 8); math.Abs(d-c.expected) > 1e-12 {
+			t.Fatal("expected", c.expected, "got", d, "for", c)
+		}
+	}
+
+	// corner node has four neighbors too
+	count := 0
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 10; j++ {
+			if d := Toroidal.distance(0, 0, j, i, 10, 8); d > 0 && d < 1+1e-9 {
+				count++
+			}
+		}
+	}
+	if count != 4 {
+		t.Fatal("expected 4 neighbors of the corner node got", count)
+	}
+}
+
+// TestSOMHexagonal trains hexagonal map on basic patterns.
+func TestSOMHexagonal(t *testing.T) {
+	data, result, _ := basicPatterns()
+	som := NewSOMWithTopology(12, 12, 10, 3, Hexagonal)
+	som.Train(5000, data, result)
+	checkBasicPatterns(t, som)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected SetTopology to panic on trained map")
+		}
+	}()
+	som.SetTopology(Rectangular)
+}
+
+// TestSOMToroidal trains toroidal map on basic patterns.
+func TestSOMToroidal(t *testing.T) {
+	data, result, test := basicPatterns()
+	som := NewSOMWithTopology(12, 12, 10, 3, Toroidal)
+	som.Train(5000, data, result)
+	for i := range data {
+		if res := som.PredictInt(data[i]); res[i] < 85 {
+			t.Fatal("expected", i, "to be above 85% got", res)
+		}
+		// similar patterns land in between clusters more often on the torus
+		res := som.PredictInt(test[i])
+		for j := range res {
+			if j != i && res[j] >= res[i] {
+				t.Fatal("expected", i, "to be the best match got", res)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Artificial Neural Networks (ann) library in Go
+// U-matrix and component planes for Self-Organizing Maps
+// released under MIT license
+package ann
+
+import "fmt"
+
+// UMatrix returns average distance between feature vector of every node and feature
+// vectors of its neighbors on the grid, indexed by row and column.
+func (som *SOM) UMatrix() [][]float64 {
+	plane := som.newPlane()
+	for k, node := range som.nodes {
+		neighbors := som.neighbors(k)
+		temp := 0.0
+		for _, n := range neighbors {
+			temp += som.fvDistance(node.fv, som.nodes[n].fv)
+		}
+		if len(neighbors) > 0 {
+			temp /= float64(len(neighbors))
+		}
+		plane[node.y][node.x] = temp
+	}
+	return plane
+}
+
+// ComponentPlane returns value of feature m of every node, indexed by row and column.
+func (som *SOM) ComponentPlane(m int) [][]float64 {
+	if m < 0 || m >= som.fvSize {
+		panic(fmt.Sprintf("feature %d should be in range [0, %d)", m, som.fvSize))
+	}
+	plane := som.newPlane()
+	for _, node := range som.nodes {
+		plane[node.y][node.x] = node.fv[m]
+	}
+	return plane
+}
+
+// newPlane creates height by width values.
+func (som *SOM) newPlane() [][]float64 {
+	plane := make([][]float64, som.height, som.height)
+	for y := range plane {
+		plane[y] = make([]float64, som.width, som.width)
+	}
+	return plane
+}
+
+// neighbors returns indexes of nodes at unit distance from node k on the grid.
+func (som *SOM) neighbors(k int) []int {
+	node := som.nodes[k]
+	res := []int{}
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			x, y := node.x+dx, node.y+dy
+			if som.topology == Toroidal {
+				x = (x + som.width) % som.width
+				y = (y + som.height) % som.height
+			}
+			if x < 0 || x >= som.width || y < 0 || y >= som.height {
+				continue
+			}
+			n := y*som.width + x
+			if d := som.distance(node, som.nodes[n]); n == k || d > 1+1e-9 || contains(res, n) {
+				continue
+			}
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// contains reports if value is in values.
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
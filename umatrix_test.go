@@ -0,0 +1,70 @@
+package ann
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUMatrix checks U-matrix of the map with nodes 10 apart in the input space.
+func TestUMatrix(t *testing.T) {
+	som := gridSOM()
+	for _, row := range som.UMatrix() {
+		for _, v := range row {
+			if math.Abs(v-10) > 1e-12 {
+				t.Fatal("expected distance 10 got", v)
+			}
+		}
+	}
+
+	// boundary between columns 1 and 2 stands out
+	for _, node := range som.nodes {
+		if node.x >= 2 {
+			node.fv[0] += 100
+		}
+	}
+	um := som.UMatrix()
+	if um[1][0] >= um[1][1] || um[1][3] >= um[1][2] {
+		t.Fatal("expected higher values next to the boundary", um)
+	}
+}
+
+// TestNeighbors checks number of grid neighbors for every topology.
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		topology       Topology
+		corner, inside int
+	}{
+		{Rectangular, 2, 4},
+		{Hexagonal, 2, 6},
+		{Toroidal, 4, 4},
+	}
+	for _, tt := range tests {
+		som := NewSOM(4, 5, 2, 1)
+		som.SetTopology(tt.topology)
+		if n := len(som.neighbors(0)); n != tt.corner {
+			t.Error(tt.topology, "expected", tt.corner, "corner neighbors got", n)
+		}
+		if n := len(som.neighbors(1*5 + 2)); n != tt.inside {
+			t.Error(tt.topology, "expected", tt.inside, "inside neighbors got", n)
+		}
+	}
+}
+
+// TestComponentPlane checks feature values of the nodes.
+func TestComponentPlane(t *testing.T) {
+	som := gridSOM()
+	plane := som.ComponentPlane(1)
+	if len(plane) != 3 || len(plane[0]) != 4 {
+		t.Fatal("expected 3 by 4 plane got", len(plane), len(plane[0]))
+	}
+	if plane[2][3] != 20 || plane[1][0] != 10 {
+		t.Fatal("unexpected plane", plane)
+	}
+
+	defer func() {
+		if r := recover(); r != "feature 2 should be in range [0, 2)" {
+			t.Fatal("expected panic on feature out of range got", r)
+		}
+	}()
+	som.ComponentPlane(2)
+}